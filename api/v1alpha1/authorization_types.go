@@ -0,0 +1,103 @@
+// Copyright Envoy Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package v1alpha1
+
+// Authorization defines an RBAC authorization policy. Action decides
+// whether matching Rules allow or deny traffic; Shadow, when set, evaluates
+// a second set of rules in parallel (Envoy's shadow_rules) so operators can
+// observe would-be decisions before enforcing them, without blocking
+// traffic on the outcome.
+type Authorization struct {
+	// Action decides whether Rules allow or deny the traffic they match.
+	// Valid Action values are
+	// "Allow",
+	// "Deny",
+	// +kubebuilder:validation:Enum=Allow;Deny
+	Action AuthorizationAction `json:"action,omitempty"`
+	// Rules are evaluated in order; the first matching rule decides the
+	// request.
+	Rules []AuthorizationRule `json:"rules,omitempty"`
+	// Shadow, when set, evaluates a second set of rules on the same RBAC
+	// filter instance via Envoy's shadow_rules: decisions are logged and
+	// counted but never block traffic. This is the standard safe-rollout
+	// pattern for authorization changes.
+	Shadow *ShadowAuthorization `json:"shadow,omitempty"`
+}
+
+// AuthorizationAction specifies whether an Authorization's rules allow or
+// deny matching traffic.
+type AuthorizationAction string
+
+const (
+	AuthorizationActionAllow AuthorizationAction = "Allow"
+	AuthorizationActionDeny  AuthorizationAction = "Deny"
+)
+
+// ShadowAuthorization configures a parallel, non-blocking evaluation of the
+// same principal/permission rules.
+type ShadowAuthorization struct {
+	// Rules are evaluated independently of the enforcing policy's Rules,
+	// so a shadow rollout can differ from what's currently enforced.
+	Rules []AuthorizationRule `json:"rules,omitempty"`
+	// StatPrefix is prepended to the RBAC filter's
+	// shadow_allowed/shadow_denied counters, e.g. "rbac.shadow.<prefix>".
+	// Defaults to the policy's name when unset.
+	StatPrefix string `json:"statPrefix,omitempty"`
+}
+
+// AuthorizationRule matches traffic by principal and, optionally, request
+// attributes.
+type AuthorizationRule struct {
+	// Name identifies this rule in translation error messages. All rules of
+	// a policy are merged into one Envoy RBAC policy named after the policy,
+	// so Name does not appear in Envoy's logs or stats.
+	Name string `json:"name,omitempty"`
+	// Principals are OR'd together; the rule matches if any principal matches.
+	Principals []Principal `json:"principals,omitempty"`
+}
+
+// Principal identifies a caller by one of several attributes. Exactly one
+// field should be set.
+type Principal struct {
+	// JWTMetadata matches a claim from a previously verified JWT, read from
+	// the envoy.filters.http.jwt_authn filter's dynamic metadata. The
+	// JWTAuthentication filter must run earlier in the HTTP filter chain for
+	// this metadata to exist.
+	JWTMetadata *JWTMetadataPrincipal `json:"jwtMetadata,omitempty"`
+	// URLPath matches the request's :path pseudo-header.
+	URLPath *StringMatch `json:"urlPath,omitempty"`
+	// Header matches a single request header.
+	Header *HeaderPrincipal `json:"header,omitempty"`
+	// ConnectionSAN matches a URI SAN on the client's mTLS certificate.
+	ConnectionSAN *StringMatch `json:"connectionSAN,omitempty"`
+}
+
+// JWTMetadataPrincipal matches a claim emitted as dynamic metadata by the
+// JWT authentication filter (see [[jwt-authentication]]).
+type JWTMetadataPrincipal struct {
+	// Filter is the dynamic metadata namespace the claim was written under,
+	// normally "envoy.filters.http.jwt_authn".
+	Filter string `json:"filter,omitempty"`
+	// Path navigates the metadata struct to the claim, e.g. ["sub"] or
+	// ["iss"] or a custom claim name.
+	Path []string `json:"path,omitempty"`
+	// Value matches the claim's value.
+	Value StringMatch `json:"value,omitempty"`
+}
+
+// HeaderPrincipal matches a request header's value.
+type HeaderPrincipal struct {
+	Name  string      `json:"name,omitempty"`
+	Value StringMatch `json:"value,omitempty"`
+}
+
+// StringMatch matches a string value. Exactly one field should be set.
+type StringMatch struct {
+	Exact  *string `json:"exact,omitempty"`
+	Prefix *string `json:"prefix,omitempty"`
+	Suffix *string `json:"suffix,omitempty"`
+	Regex  *string `json:"regex,omitempty"`
+}
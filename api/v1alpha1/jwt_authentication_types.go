@@ -0,0 +1,82 @@
+// Copyright Envoy Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package v1alpha1
+
+import (
+	"github.com/golang/protobuf/ptypes/duration"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// JWTAuthentication defines a JWT authentication policy: the set of trusted
+// providers and the routes that require a valid token from one of them.
+type JWTAuthentication struct {
+	// Providers lists the JWT providers trusted by this policy, keyed by
+	// Provider.Name when referenced from Requires.
+	Providers []JWTProvider `json:"providers,omitempty"`
+	// Requires binds JWT requirements to HTTPRoute matches. A request that
+	// matches no rule is not subject to JWT authentication.
+	Requires []JWTRequirementRule `json:"requires,omitempty"`
+}
+
+// JWTProvider defines a single trusted JWT issuer.
+type JWTProvider struct {
+	// Name uniquely identifies this provider within a JWTAuthentication policy.
+	Name string `json:"name,omitempty"`
+	// Issuer is the expected "iss" claim. If unset, the issuer is not checked.
+	Issuer string `json:"issuer,omitempty"`
+	// Audiences lists the acceptable "aud" claim values. If unset, the
+	// audience is not checked.
+	Audiences []string `json:"audiences,omitempty"`
+	// RemoteJWKS fetches the signing keys from a remote JWKS endpoint.
+	RemoteJWKS *RemoteJWKS `json:"remoteJWKS,omitempty"`
+	// Forward, if true, retains the original JWT in its source header (or
+	// query parameter) after verification, for the upstream to re-validate
+	// or inspect. Independent of ForwardPayloadHeader.
+	Forward bool `json:"forward,omitempty"`
+	// ForwardPayloadHeader, if set, forwards the verified JWT payload
+	// (base64url-encoded) to the upstream in this request header.
+	ForwardPayloadHeader string `json:"forwardPayloadHeader,omitempty"`
+	// ClaimToHeaders copies individual claims from the verified JWT payload
+	// into request headers, e.g. for upstream authorization or logging.
+	ClaimToHeaders []ClaimToHeader `json:"claimToHeaders,omitempty"`
+}
+
+// RemoteJWKS configures fetching of JSON Web Key Sets from a remote server.
+type RemoteJWKS struct {
+	// URI is the HTTPS endpoint serving the JWKS document.
+	URI string `json:"uri,omitempty"`
+	// CacheDuration is how long a fetched JWKS is cached before being
+	// re-fetched. Defaults to 5 minutes when unset.
+	CacheDuration duration.Duration `json:"cacheDuration,omitempty"`
+	// AsyncFetch fetches the JWKS when the filter chain is initialized
+	// rather than on the first request that needs it, avoiding added
+	// latency on cold start. The listener is activated without waiting for
+	// that initial fetch to complete.
+	AsyncFetch bool `json:"asyncFetch,omitempty"`
+}
+
+// ClaimToHeader copies a single JWT claim into a request header.
+type ClaimToHeader struct {
+	// Header is the destination request header name.
+	Header string `json:"header,omitempty"`
+	// Claim is the name of the claim in the verified JWT payload.
+	Claim string `json:"claim,omitempty"`
+}
+
+// JWTRequirementRule binds a set of acceptable JWT providers to the requests
+// matching an HTTPRoute rule.
+type JWTRequirementRule struct {
+	// Match selects the HTTPRoute rule this requirement applies to.
+	Match *gwapiv1.HTTPRouteMatch `json:"match,omitempty"`
+	// ProviderNames lists the JWTProvider.Name values accepted for requests
+	// matching Match. A request is allowed through if any listed provider
+	// verifies it.
+	ProviderNames []string `json:"providerNames,omitempty"`
+	// Disabled exempts requests matching Match from JWT authentication
+	// entirely, overriding any provider-level requirement that would
+	// otherwise apply.
+	Disabled bool `json:"disabled,omitempty"`
+}
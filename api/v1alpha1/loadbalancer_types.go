@@ -0,0 +1,143 @@
+// Copyright Envoy Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package v1alpha1
+
+import (
+	"github.com/golang/protobuf/ptypes/duration"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// LoadBalancer defines the load balancer policy to apply to a destination.
+// Exactly one of ConsistentHash, LeastRequest, or Custom should be set to
+// match Type; RoundRobin and Random carry no further configuration.
+type LoadBalancer struct {
+	// Type decides the type of LoadBalancer policy.
+	// Valid LoadBalancerType values are
+	// "ConsistentHash",
+	// "LeastRequest",
+	// "Random",
+	// "RoundRobin",
+	// "Custom",
+	// +kubebuilder:validation:Enum=ConsistentHash;LeastRequest;Random;RoundRobin;Custom
+	Type LoadBalancerType `json:"type,omitempty"`
+
+	// ConsistentHash configures ring_hash or maglev hashing. Only set when
+	// Type is ConsistentHash.
+	ConsistentHash *ConsistentHash `json:"consistentHash,omitempty"`
+	// LeastRequest configures power-of-two-choices least request load
+	// balancing. Only set when Type is LeastRequest.
+	LeastRequest *LeastRequest `json:"leastRequest,omitempty"`
+	// Custom wires in a load balancer policy registered out-of-tree in
+	// Envoy. Only set when Type is Custom.
+	Custom *CustomLoadBalancer `json:"custom,omitempty"`
+}
+
+// LoadBalancerType specifies the types of LoadBalancer.
+type LoadBalancerType string
+
+const (
+	ConsistentHashLoadBalancerType LoadBalancerType = "ConsistentHash"
+	LeastRequestLoadBalancerType   LoadBalancerType = "LeastRequest"
+	RandomLoadBalancerType         LoadBalancerType = "Random"
+	RoundRobinLoadBalancerType     LoadBalancerType = "RoundRobin"
+	CustomLoadBalancerType         LoadBalancerType = "Custom"
+)
+
+// ConsistentHash selects one hashing algorithm and the request attributes
+// that feed its hash key.
+type ConsistentHash struct {
+	// Ring configures the ring_hash algorithm. Mutually exclusive with Maglev.
+	Ring *RingHash `json:"ring,omitempty"`
+	// Maglev configures the maglev algorithm. Mutually exclusive with Ring.
+	Maglev *Maglev `json:"maglev,omitempty"`
+	// HashPolicies determine, in order, the value hashed to pick an upstream
+	// host. No policy is terminal: the hashes of every policy that produces
+	// a value are combined.
+	HashPolicies []HashPolicy `json:"hashPolicies,omitempty"`
+}
+
+// RingHash configures Envoy's ring_hash consistent-hashing load balancer.
+type RingHash struct {
+	// MinimumRingSize is the minimum number of entries in the hash ring.
+	// Defaults to 1024 when unset.
+	MinimumRingSize uint64 `json:"minimumRingSize,omitempty"`
+	// MaximumRingSize is the maximum number of entries in the hash ring.
+	// Defaults to 8M when unset.
+	MaximumRingSize uint64 `json:"maximumRingSize,omitempty"`
+}
+
+// Maglev configures Envoy's maglev consistent-hashing load balancer.
+type Maglev struct {
+	// TableSize is the size of the maglev lookup table. Should be a prime
+	// number for best distribution; defaults to 65537 when unset.
+	TableSize uint64 `json:"tableSize,omitempty"`
+}
+
+// HashPolicy selects one request attribute to feed into a consistent hash.
+type HashPolicy struct {
+	// Type decides which request attribute this policy hashes on.
+	// Valid HashPolicyType values are
+	// "Header",
+	// "Cookie",
+	// "SourceIP",
+	// "QueryParameter",
+	// +kubebuilder:validation:Enum=Header;Cookie;SourceIP;QueryParameter
+	Type HashPolicyType `json:"type,omitempty"`
+
+	// Header is the header name to hash on. Only set when Type is Header.
+	Header *string `json:"header,omitempty"`
+	// Cookie configures cookie-based hashing, generating a cookie for the
+	// client if one isn't already present. Only set when Type is Cookie.
+	Cookie *CookieHashPolicy `json:"cookie,omitempty"`
+	// QueryParameter is the query parameter name to hash on. Only set when
+	// Type is QueryParameter.
+	QueryParameter *string `json:"queryParameter,omitempty"`
+}
+
+// HashPolicyType specifies the request attribute a HashPolicy hashes on.
+type HashPolicyType string
+
+const (
+	HeaderHashPolicyType         HashPolicyType = "Header"
+	CookieHashPolicyType         HashPolicyType = "Cookie"
+	SourceIPHashPolicyType       HashPolicyType = "SourceIP"
+	QueryParameterHashPolicyType HashPolicyType = "QueryParameter"
+)
+
+// CookieHashPolicy configures session-affinity hashing on a cookie.
+type CookieHashPolicy struct {
+	// Name is the cookie name.
+	Name string `json:"name,omitempty"`
+	// TTL is how long the generated cookie is valid for. A zero TTL
+	// generates a session cookie.
+	TTL duration.Duration `json:"ttl,omitempty"`
+	// Path is the path attribute set on the generated cookie.
+	Path string `json:"path,omitempty"`
+}
+
+// LeastRequest configures Envoy's least_request load balancer, which uses
+// power-of-two-choices (P2C) to pick the least-loaded of ChoiceCount
+// candidate hosts.
+type LeastRequest struct {
+	// ChoiceCount is the number of random candidate hosts considered per
+	// pick. Defaults to 2 when unset.
+	ChoiceCount *uint32 `json:"choiceCount,omitempty"`
+	// ActiveRequestBias scales the weight penalty applied to hosts with
+	// more active requests; values above 1.0 penalize loaded hosts more
+	// aggressively. Defaults to 1.0 when unset.
+	ActiveRequestBias *float64 `json:"activeRequestBias,omitempty"`
+}
+
+// CustomLoadBalancer wires in a load balancer extension registered
+// out-of-tree in Envoy (e.g. wrr_locality, pick_first) via an arbitrary
+// typed config payload.
+type CustomLoadBalancer struct {
+	// Name is the load balancer policy's registered extension name.
+	Name string `json:"name,omitempty"`
+	// TypedConfig is the policy-specific configuration, serialized as
+	// Envoy's TypedExtensionConfig/TypedStruct payload.
+	TypedConfig runtime.RawExtension `json:"typedConfig,omitempty"`
+}
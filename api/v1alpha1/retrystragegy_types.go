@@ -14,6 +14,7 @@ type RetryStrategy struct {
 	// "Http",
 	// "Grpc",
 	//
+	// +kubebuilder:validation:Enum=Http;Grpc
 	Type ProtocolType `json:"type,omitempty"`
 
 	Http *HttpRetry `json:"http,omitempty"`
@@ -25,22 +26,126 @@ type RetryStrategy struct {
 	RetryLimit RetryLimitPolicy `json:"retryLimit,omitempty"`
 }
 
-// LoadBalancerType specifies the types of LoadBalancer.
-// +kubebuilder:validation:Enum=ConsistentHash;LeastRequest;Random;RoundRobin
+// ProtocolType specifies the protocol a RetryStrategy's retry-on conditions
+// are evaluated against.
 type ProtocolType string
 
+const (
+	ProtocolTypeHttp ProtocolType = "Http"
+	ProtocolTypeGrpc ProtocolType = "Grpc"
+)
+
 type HttpRetry struct {
 	RetryOn              RetryOn              `json:"retryOn,omitempty"`
 	RetriableStatusCodes RetriableStatusCodes `json:"retriableStatusCodes,omitempty"`
+	// RetryPriority adjusts the priority set used when selecting a host to retry against.
+	RetryPriority *RetryPriority `json:"retryPriority,omitempty"`
+	// RetryHostPredicate excludes hosts from being retried against.
+	RetryHostPredicate *RetryHostPredicate `json:"retryHostPredicate,omitempty"`
+	// HedgedRetry enables per-try hedging of retried requests.
+	HedgedRetry *HedgedRetry `json:"hedgedRetry,omitempty"`
+	// RateLimitedBackOff honors rate limit response headers on the upstream
+	// to compute the retry back-off interval, falling back to PerRetry.BackOff
+	// when the response carries none of the configured headers.
+	RateLimitedBackOff *RateLimitedRetryBackOff `json:"rateLimitedBackOff,omitempty"`
 }
 
 type GrpcRetry struct {
 	RetryOn RetryOn `json:"retryOn,omitempty"`
 }
 
+// RetryOn specifies the conditions under which Envoy will retry a request.
+// Valid RetryOn values are
+// "5xx",
+// "gateway-error",
+// "reset",
+// "connect-failure",
+// "retriable-4xx",
+// "refused-stream",
+// "retriable-status-codes",
+// "retriable-headers",
+// +kubebuilder:validation:Enum=5xx;gateway-error;reset;connect-failure;retriable-4xx;refused-stream;retriable-status-codes;retriable-headers
 type RetryOn string
+
+const (
+	RetryOn5xx                  RetryOn = "5xx"
+	RetryOnGatewayError         RetryOn = "gateway-error"
+	RetryOnReset                RetryOn = "reset"
+	RetryOnConnectFailure       RetryOn = "connect-failure"
+	RetryOnRetriable4xx         RetryOn = "retriable-4xx"
+	RetryOnRefusedStream        RetryOn = "refused-stream"
+	RetryOnRetriableStatusCodes RetryOn = "retriable-status-codes"
+	RetryOnRetriableHeaders     RetryOn = "retriable-headers"
+)
+
 type RetriableStatusCodes []int
 
+// RetryPriority maps to Envoy's previous_priorities retry priority, which
+// progressively de-prioritizes host priority levels that previous retries
+// in the same request failed against.
+type RetryPriority struct {
+	// UpdateFrequency is the number of retries that occur before previous_priorities
+	// recalculates the priority load. Defaults to 2 when unset.
+	UpdateFrequency int `json:"updateFrequency,omitempty"`
+}
+
+// RetryHostPredicate excludes hosts from being retried against, based on
+// Envoy's retry host predicate extensions.
+type RetryHostPredicate struct {
+	// PreviousHosts excludes hosts that the request has already been
+	// sent to during this retry sequence.
+	PreviousHosts bool `json:"previousHosts,omitempty"`
+	// OmitCanaryHosts excludes hosts marked as canaries via host metadata.
+	OmitCanaryHosts bool `json:"omitCanaryHosts,omitempty"`
+	// OmitHostMetadata excludes hosts matching the given metadata from retries.
+	OmitHostMetadata map[string]string `json:"omitHostMetadata,omitempty"`
+}
+
+// HedgedRetry configures per-try timeout hedging, where a retry is issued in
+// parallel with the original request once its per-try timeout elapses,
+// rather than waiting for it to fail outright.
+type HedgedRetry struct {
+	// HedgeOnPerTryTimeout enables sending a hedged request when the per-try
+	// timeout expires, instead of waiting for the outstanding request to fail.
+	HedgeOnPerTryTimeout bool `json:"hedgeOnPerTryTimeout,omitempty"`
+	// InitialJitter is the jitter added to the first hedged retry's delay.
+	InitialJitter duration.Duration `json:"initialJitter,omitempty"`
+	// MaxJitter caps the jitter applied to any single hedged retry's delay.
+	MaxJitter duration.Duration `json:"maxJitter,omitempty"`
+}
+
+// RateLimitedRetryBackOff configures Envoy to compute the retry back-off
+// interval from rate limit response headers (e.g. Retry-After,
+// X-RateLimit-Reset) rather than from the static BackOffPolicy.
+type RateLimitedRetryBackOff struct {
+	// ResetHeaders lists, in priority order, the response headers Envoy
+	// should consult for a retry-after interval.
+	ResetHeaders []ResetHeader `json:"resetHeaders,omitempty"`
+	// MaxInterval caps the back-off interval computed from ResetHeaders.
+	MaxInterval duration.Duration `json:"maxInterval,omitempty"`
+}
+
+// ResetHeader identifies a single rate-limit response header and how its
+// value should be interpreted.
+type ResetHeader struct {
+	// Name is the response header name, e.g. "Retry-After".
+	Name string `json:"name,omitempty"`
+	// Format describes how to parse Name's value.
+	// Valid Format values are
+	// "Seconds",
+	// "UnixTimestamp",
+	// +kubebuilder:validation:Enum=Seconds;UnixTimestamp
+	Format ResetHeaderFormat `json:"format,omitempty"`
+}
+
+// ResetHeaderFormat specifies how a rate-limit reset header value is encoded.
+type ResetHeaderFormat string
+
+const (
+	ResetHeaderFormatSeconds       ResetHeaderFormat = "Seconds"
+	ResetHeaderFormatUnixTimestamp ResetHeaderFormat = "UnixTimestamp"
+)
+
 type PerRetryPolicy struct {
 	Timeout     duration.Duration `json:"timeout,omitempty"`
 	IdleTimeout duration.Duration `json:"idleTimeout,omitempty"`
@@ -54,14 +159,27 @@ type BackOffPolicy struct {
 
 type RetryLimitPolicy struct {
 	// Valid RetryLimitType values are
-	// "Http",
-	// "Grpc",
+	// "Static",
+	// "RetryBudget",
+	// +kubebuilder:validation:Enum=Static;RetryBudget
 	Type        RetryLimitType    `json:"type,omitempty"`
 	Static      StaticPolicy      `json:"static,omitempty"`
 	RetryBudget RetryBudgetPolicy `json:"retryBudget,omitempty"`
 }
+
+// RetryLimitType specifies how the number of concurrent retries against a
+// cluster is bounded.
 type RetryLimitType string
 
+const (
+	// RetryLimitTypeStatic caps concurrent retries at a fixed number,
+	// mapped onto Envoy's circuit_breakers.max_retries threshold.
+	RetryLimitTypeStatic RetryLimitType = "Static"
+	// RetryLimitTypeRetryBudget caps concurrent retries as a percentage of
+	// active requests, mapped onto Envoy's circuit_breakers.retry_budget.
+	RetryLimitTypeRetryBudget RetryLimitType = "RetryBudget"
+)
+
 type StaticPolicy struct {
 	MaxParallel int `json:"maxParallel,omitempty"`
 }
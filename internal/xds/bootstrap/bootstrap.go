@@ -9,6 +9,7 @@ import (
 	// Register embed
 	_ "embed"
 	"fmt"
+	"net/url"
 	"strings"
 	"text/template"
 
@@ -38,6 +39,14 @@ const (
 	EnvoyReadinessPath    = "/ready"
 	// required stats are used by readiness checks.
 	RequiredEnvoyStatsMatcherInclusionPrefixes = "cluster_manager,listener_manager,server,cluster.xds-grpc"
+
+	// XdsServerApiTypeGRPC selects Envoy's state-of-the-world (SotW) gRPC
+	// xDS transport. This is the default.
+	XdsServerApiTypeGRPC = "GRPC"
+	// XdsServerApiTypeDeltaGRPC selects Envoy's incremental (Delta) gRPC
+	// xDS transport, which only pushes changed or removed resources instead
+	// of full SotW snapshots on every update.
+	XdsServerApiTypeDeltaGRPC = "DELTA_GRPC"
 )
 
 //go:embed bootstrap.yaml.tpl
@@ -65,6 +74,10 @@ type bootstrapParameters struct {
 	EnablePrometheus bool
 	// OtelMetricSinks defines the configuration of the OpenTelemetry sinks.
 	OtelMetricSinks []metricSink
+	// StatsdMetricSink defines the configuration of the StatsD sink, nil if unset.
+	StatsdMetricSink *statsdSink
+	// DogstatsdMetricSink defines the configuration of the DogStatsD sink, nil if unset.
+	DogstatsdMetricSink *dogstatsdSink
 	// Proxy stats matcher defines configuration for reporting custom Envoy stats.
 	// To reduce memory and CPU overhead from Envoy stats system, Gateway proxies by
 	// default create and expose only a subset of Envoy stats. This option is to
@@ -93,6 +106,10 @@ type xdsServerParameters struct {
 	Address string
 	// Port is the port of the XDS Server that Envoy is managed by.
 	Port int32
+	// ApiType is the xDS transport api_type Envoy uses to talk to the XDS
+	// Server, either "GRPC" (state-of-the-world) or "DELTA_GRPC"
+	// (incremental). Defaults to "GRPC".
+	ApiType string
 }
 
 type metricSink struct {
@@ -102,15 +119,61 @@ type metricSink struct {
 	Port int32
 }
 
+// statsdSink defines the configuration of a StatsD stat sink, reachable
+// either over UDP (Address/Port) or over a Unix domain socket (Pipe).
+type statsdSink struct {
+	// Address is the UDP host of the StatsD sink. Unset when Pipe is set.
+	Address string
+	// Port is the UDP port of the StatsD sink. Unset when Pipe is set.
+	Port int32
+	// Pipe is the Unix domain socket path of the StatsD sink. Unset when Address is set.
+	Pipe string
+	// Prefix is prepended to every stat name emitted to this sink.
+	Prefix string
+}
+
+// dogstatsdSink defines the configuration of a DogStatsD stat sink, reachable
+// either over UDP (Address/Port) or over a Unix domain socket (Pipe).
+type dogstatsdSink struct {
+	// Address is the UDP host of the DogStatsD sink. Unset when Pipe is set.
+	Address string
+	// Port is the UDP port of the DogStatsD sink. Unset when Pipe is set.
+	Port int32
+	// Pipe is the Unix domain socket path of the DogStatsD sink. Unset when Address is set.
+	Pipe string
+	// Prefix is prepended to every stat name emitted to this sink.
+	Prefix string
+	// Tags are fixed tags attached to every metric emitted to this sink,
+	// e.g. cluster_name, envoy_cluster_upstream_rq.
+	Tags []dogstatsdTag
+}
+
+// dogstatsdTag is a single fixed tag name/value pair emitted alongside every
+// metric sent to a DogStatsD sink.
+type dogstatsdTag struct {
+	Name  string
+	Value string
+}
+
 type adminServerParameters struct {
-	// Address is the address of the Envoy admin interface.
+	// Address is the address of the Envoy admin interface. Unset when Pipe is set.
 	Address string
-	// Port is the port of the Envoy admin interface.
+	// Port is the port of the Envoy admin interface. Unset when Pipe is set.
 	Port int32
+	// Pipe is the Unix domain socket path of the Envoy admin interface. Unset when Address is set.
+	Pipe string
 	// AccessLogPath is the path of the Envoy admin access log.
 	AccessLogPath string
 }
 
+// readyServerParameters configures a dedicated static listener (see
+// envoy-gateway-proxy-ready in bootstrap.yaml.tpl) that answers readiness
+// probes via the health_check HTTP filter. It is intentionally independent
+// of AdminServer: Envoy's admin /ready endpoint would stop being reachable
+// over TCP once AdminServer.Pipe is set, and a probe hard-coded to curl
+// 127.0.0.1:<port> would break at that point. The ready listener always
+// binds its own TCP address/port, so readiness checks keep working
+// regardless of how the admin interface is configured.
 type readyServerParameters struct {
 	// Address is the address of the Envoy readiness probe
 	Address string
@@ -129,6 +192,62 @@ type ProxyStatsMatcherParameters struct {
 	InclusionRegexps []string
 }
 
+// parseStatsdSinkURL parses a statsd-style sink address of the form
+// "udp://host:port" or "unix:///path/to.sock" into a statsdSink.
+func parseStatsdSinkURL(rawURL string) (*statsdSink, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse sink address %q: %v", rawURL, err)
+	}
+
+	switch u.Scheme {
+	case "unix":
+		return &statsdSink{Pipe: u.Path}, nil
+	case "udp":
+		host := u.Hostname()
+		port := u.Port()
+		if host == "" || port == "" {
+			return nil, fmt.Errorf("sink address %q must specify host and port", rawURL)
+		}
+		var p int32
+		if _, err := fmt.Sscanf(port, "%d", &p); err != nil {
+			return nil, fmt.Errorf("invalid port in sink address %q: %v", rawURL, err)
+		}
+		return &statsdSink{Address: host, Port: p}, nil
+	default:
+		return nil, fmt.Errorf("unsupported sink scheme %q, must be udp or unix", u.Scheme)
+	}
+}
+
+// buildAdminServerParameters derives the admin interface listening address
+// from the AdminAccess field, falling back to the default TCP address when
+// unset. A Unix socket path takes precedence over an explicit TCP
+// address/port, which in turn replaces the default.
+func buildAdminServerParameters(proxyMetrics *egcfgv1a1.ProxyMetrics) adminServerParameters {
+	params := adminServerParameters{
+		Address:       envoyAdminAddress,
+		Port:          envoyAdminPort,
+		AccessLogPath: envoyAdminAccessLogPath,
+	}
+
+	if proxyMetrics == nil || proxyMetrics.AdminAccess == nil {
+		return params
+	}
+
+	admin := proxyMetrics.AdminAccess
+	switch {
+	case admin.UnixSocket != nil:
+		params.Address = ""
+		params.Port = 0
+		params.Pipe = *admin.UnixSocket
+	case admin.Address != nil:
+		params.Address = admin.Address.Host
+		params.Port = admin.Address.Port
+	}
+
+	return params
+}
+
 // render the stringified bootstrap config in yaml format.
 func (b *bootstrapConfig) render() error {
 	buf := new(strings.Builder)
@@ -140,12 +259,28 @@ func (b *bootstrapConfig) render() error {
 	return nil
 }
 
-// GetRenderedBootstrapConfig renders the bootstrap YAML string
-func GetRenderedBootstrapConfig(proxyMetrics *egcfgv1a1.ProxyMetrics) (string, error) {
+// GetRenderedBootstrapConfig renders the bootstrap YAML string. xdsServerAPIType
+// selects the xDS transport Envoy uses to talk back to the XDS Server,
+// defaulting to XdsServerApiTypeGRPC when empty. additionalStatsPrefixes is
+// appended to the required stats matcher inclusion prefixes, e.g. the
+// shadow RBAC stat prefixes a translator emits when a shadow authorization
+// policy is present (see translator.ShadowRBACStatPrefixes).
+func GetRenderedBootstrapConfig(proxyMetrics *egcfgv1a1.ProxyMetrics, xdsServerAPIType string, additionalStatsPrefixes []string) (string, error) {
+	switch xdsServerAPIType {
+	case "":
+		xdsServerAPIType = XdsServerApiTypeGRPC
+	case XdsServerApiTypeGRPC, XdsServerApiTypeDeltaGRPC:
+	default:
+		return "", fmt.Errorf("unsupported xds server api_type %q, must be %s or %s",
+			xdsServerAPIType, XdsServerApiTypeGRPC, XdsServerApiTypeDeltaGRPC)
+	}
+
 	var (
-		enablePrometheus  bool
-		metricSinks       []metricSink
-		ProxyStatsMatcher ProxyStatsMatcherParameters
+		enablePrometheus    bool
+		metricSinks         []metricSink
+		statsdMetricSink    *statsdSink
+		dogstatsdMetricSink *dogstatsdSink
+		ProxyStatsMatcher   ProxyStatsMatcherParameters
 	)
 
 	if proxyMetrics != nil {
@@ -155,21 +290,44 @@ func GetRenderedBootstrapConfig(proxyMetrics *egcfgv1a1.ProxyMetrics) (string, e
 
 		addresses := sets.NewString()
 		for _, sink := range proxyMetrics.Sinks {
-			if sink.OpenTelemetry == nil {
-				continue
-			}
+			switch {
+			case sink.OpenTelemetry != nil:
+				// skip duplicate sinks
+				addr := fmt.Sprintf("%s:%d", sink.OpenTelemetry.Host, sink.OpenTelemetry.Port)
+				if addresses.Has(addr) {
+					continue
+				}
+				addresses.Insert(addr)
 
-			// skip duplicate sinks
-			addr := fmt.Sprintf("%s:%d", sink.OpenTelemetry.Host, sink.OpenTelemetry.Port)
-			if addresses.Has(addr) {
-				continue
+				metricSinks = append(metricSinks, metricSink{
+					Address: sink.OpenTelemetry.Host,
+					Port:    sink.OpenTelemetry.Port,
+				})
+			case sink.StatsD != nil:
+				s, err := parseStatsdSinkURL(sink.StatsD.Address)
+				if err != nil {
+					return "", fmt.Errorf("invalid statsd sink address: %v", err)
+				}
+				s.Prefix = sink.StatsD.Prefix
+				statsdMetricSink = s
+			case sink.DogStatsD != nil:
+				s, err := parseStatsdSinkURL(sink.DogStatsD.Address)
+				if err != nil {
+					return "", fmt.Errorf("invalid dogstatsd sink address: %v", err)
+				}
+				dogstatsdMetricSink = &dogstatsdSink{
+					Address: s.Address,
+					Port:    s.Port,
+					Pipe:    s.Pipe,
+					Prefix:  sink.DogStatsD.Prefix,
+				}
+				for _, tag := range sink.DogStatsD.Tags {
+					dogstatsdMetricSink.Tags = append(dogstatsdMetricSink.Tags, dogstatsdTag{
+						Name:  tag.Name,
+						Value: tag.Value,
+					})
+				}
 			}
-			addresses.Insert(addr)
-
-			metricSinks = append(metricSinks, metricSink{
-				Address: sink.OpenTelemetry.Host,
-				Port:    sink.OpenTelemetry.Port,
-			})
 		}
 
 		if proxyMetrics.ProxyStatsMatcher != nil {
@@ -181,6 +339,7 @@ func GetRenderedBootstrapConfig(proxyMetrics *egcfgv1a1.ProxyMetrics) (string, e
 		}
 	}
 	ProxyStatsMatcher.InclusionPrefixs = append(ProxyStatsMatcher.InclusionPrefixs, strings.Split(RequiredEnvoyStatsMatcherInclusionPrefixes, ",")...)
+	ProxyStatsMatcher.InclusionPrefixs = append(ProxyStatsMatcher.InclusionPrefixs, additionalStatsPrefixes...)
 	//ProxyStatsMatcher.InclusionRegexps = append(ProxyStatsMatcher.InclusionRegexps, strings.Split(RequiredEnvoyStatsMatcherInclusionRegexes, ",")...)
 
 	cfg := &bootstrapConfig{
@@ -188,20 +347,19 @@ func GetRenderedBootstrapConfig(proxyMetrics *egcfgv1a1.ProxyMetrics) (string, e
 			XdsServer: xdsServerParameters{
 				Address: envoyGatewayXdsServerHost,
 				Port:    DefaultXdsServerPort,
+				ApiType: xdsServerAPIType,
 			},
-			AdminServer: adminServerParameters{
-				Address:       envoyAdminAddress,
-				Port:          envoyAdminPort,
-				AccessLogPath: envoyAdminAccessLogPath,
-			},
+			AdminServer: buildAdminServerParameters(proxyMetrics),
 			ReadyServer: readyServerParameters{
 				Address:       envoyReadinessAddress,
 				Port:          EnvoyReadinessPort,
 				ReadinessPath: EnvoyReadinessPath,
 			},
-			EnablePrometheus:  enablePrometheus,
-			OtelMetricSinks:   metricSinks,
-			ProxyStatsMatcher: ProxyStatsMatcher,
+			EnablePrometheus:    enablePrometheus,
+			OtelMetricSinks:     metricSinks,
+			StatsdMetricSink:    statsdMetricSink,
+			DogstatsdMetricSink: dogstatsdMetricSink,
+			ProxyStatsMatcher:   ProxyStatsMatcher,
 		},
 	}
 
@@ -0,0 +1,34 @@
+// Copyright Envoy Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package server
+
+import (
+	"context"
+	"testing"
+
+	cachev3 "github.com/envoyproxy/go-control-plane/pkg/cache/v3"
+	"google.golang.org/grpc"
+)
+
+func TestNewDeltaServerRegister(t *testing.T) {
+	cache := cachev3.NewSnapshotCache(false, cachev3.IDHash{}, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s := NewDeltaServer(ctx, cache)
+	if s == nil {
+		t.Fatal("expected a non-nil DeltaServer")
+	}
+
+	grpcServer := grpc.NewServer()
+	defer grpcServer.Stop()
+
+	// Register must not panic; this is the only way to exercise that
+	// s.server actually satisfies every *DiscoveryServiceServer interface
+	// Register depends on.
+	s.Register(grpcServer)
+}
@@ -0,0 +1,259 @@
+// Copyright Envoy Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	endpointv3 "github.com/envoyproxy/go-control-plane/envoy/config/endpoint/v3"
+	jwtauthnv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/jwt_authn/v3"
+	hcmv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	egv1a1 "github.com/envoyproxy/gateway/api/v1alpha1"
+)
+
+const (
+	// jwtAuthnFilterName is the Envoy HTTP filter name for JWT authentication.
+	jwtAuthnFilterName = "envoy.filters.http.jwt_authn"
+	// rbacFilterName is the Envoy HTTP filter name for role-based access
+	// control. jwt_authn must be inserted before it so that JWT-derived
+	// dynamic metadata is available to RBAC's metadata matchers.
+	rbacFilterName = "envoy.filters.http.rbac"
+
+	// defaultJWKSCacheDuration is used when a RemoteJWKS provider does not
+	// set CacheDuration.
+	defaultJWKSCacheDuration = 5 * time.Minute
+	// remoteJWKSClusterConnectTimeout is the connect timeout applied to
+	// clusters generated for remote JWKS providers.
+	remoteJWKSClusterConnectTimeout = 10 * time.Second
+
+	// defaultJWKSPort is used when a RemoteJWKS.URI has no explicit port,
+	// matching the https default since JWKS endpoints are always TLS.
+	defaultJWKSPort = 443
+)
+
+// buildJWTAuthnFilter translates a JWTAuthentication policy into an Envoy
+// envoy.filters.http.jwt_authn HTTP filter, plus the STRICT_DNS CDS
+// clusters backing every provider's remote JWKS endpoint. The clusters
+// must be added to the same resources the filter is deployed alongside, or
+// Envoy will NACK the listener with an unknown cluster error.
+func buildJWTAuthnFilter(jwt *egv1a1.JWTAuthentication) (*hcmv3.HttpFilter, []*clusterv3.Cluster, error) {
+	if jwt == nil || len(jwt.Providers) == 0 {
+		return nil, nil, fmt.Errorf("jwtAuthentication must define at least one provider")
+	}
+
+	cfg := &jwtauthnv3.JwtAuthentication{
+		Providers: make(map[string]*jwtauthnv3.JwtProvider, len(jwt.Providers)),
+	}
+
+	var clusters []*clusterv3.Cluster
+	for _, p := range jwt.Providers {
+		provider, err := buildJWTProvider(p)
+		if err != nil {
+			return nil, nil, fmt.Errorf("provider %q: %w", p.Name, err)
+		}
+		cfg.Providers[p.Name] = provider
+
+		cluster, err := buildRemoteJWKSClusterForProvider(p)
+		if err != nil {
+			return nil, nil, fmt.Errorf("provider %q: %w", p.Name, err)
+		}
+		clusters = append(clusters, cluster)
+	}
+
+	for _, req := range jwt.Requires {
+		cfg.Rules = append(cfg.Rules, buildJWTRequirementRule(req))
+	}
+
+	any, err := anypb.New(cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal jwt_authn config: %w", err)
+	}
+
+	filter := &hcmv3.HttpFilter{
+		Name:       jwtAuthnFilterName,
+		ConfigType: &hcmv3.HttpFilter_TypedConfig{TypedConfig: any},
+	}
+	return filter, clusters, nil
+}
+
+func buildJWTProvider(p egv1a1.JWTProvider) (*jwtauthnv3.JwtProvider, error) {
+	if p.RemoteJWKS == nil {
+		return nil, fmt.Errorf("only remote JWKS providers are currently supported")
+	}
+
+	provider := &jwtauthnv3.JwtProvider{
+		Issuer:               p.Issuer,
+		Audiences:            p.Audiences,
+		Forward:              p.Forward,
+		ForwardPayloadHeader: p.ForwardPayloadHeader,
+	}
+
+	for _, c := range p.ClaimToHeaders {
+		provider.ClaimToHeaders = append(provider.ClaimToHeaders, &jwtauthnv3.JwtClaimToHeader{
+			HeaderName: c.Header,
+			ClaimName:  c.Claim,
+		})
+	}
+
+	cacheDuration := defaultJWKSCacheDuration
+	if p.RemoteJWKS.CacheDuration.Seconds > 0 {
+		cacheDuration = time.Duration(p.RemoteJWKS.CacheDuration.Seconds) * time.Second
+	}
+
+	provider.JwksSourceSpecifier = &jwtauthnv3.JwtProvider_RemoteJwks{
+		RemoteJwks: &jwtauthnv3.RemoteJwks{
+			HttpUri: &corev3.HttpUri{
+				Uri: p.RemoteJWKS.URI,
+				HttpUpstreamType: &corev3.HttpUri_Cluster{
+					Cluster: remoteJWKSClusterName(p.Name),
+				},
+				Timeout: durationpb.New(remoteJWKSClusterConnectTimeout),
+			},
+			CacheDuration: durationpb.New(cacheDuration),
+		},
+	}
+
+	// The mere presence of AsyncFetch enables fetch-at-init, regardless of
+	// FastListener's value, so it must only be set when actually requested.
+	if p.RemoteJWKS.AsyncFetch {
+		provider.JwksSourceSpecifier.(*jwtauthnv3.JwtProvider_RemoteJwks).RemoteJwks.AsyncFetch = &jwtauthnv3.JwksAsyncFetch{
+			FastListener: true,
+		}
+	}
+
+	return provider, nil
+}
+
+// buildJWTRequirementRule translates a single JWTRequirementRule into an
+// Envoy jwt_authn RequirementRule. A Disabled rule is emitted with an empty
+// requirement so matching requests are allowed through without a token.
+func buildJWTRequirementRule(req egv1a1.JWTRequirementRule) *jwtauthnv3.RequirementRule {
+	rule := &jwtauthnv3.RequirementRule{
+		Match: httpRouteMatchToRouteMatch(req.Match),
+	}
+
+	if req.Disabled || len(req.ProviderNames) == 0 {
+		return rule
+	}
+
+	if len(req.ProviderNames) == 1 {
+		rule.RequirementType = &jwtauthnv3.RequirementRule_Requires{
+			Requires: &jwtauthnv3.JwtRequirement{
+				RequiresType: &jwtauthnv3.JwtRequirement_ProviderName{ProviderName: req.ProviderNames[0]},
+			},
+		}
+		return rule
+	}
+
+	requirements := make([]*jwtauthnv3.JwtRequirement, 0, len(req.ProviderNames))
+	for _, name := range req.ProviderNames {
+		requirements = append(requirements, &jwtauthnv3.JwtRequirement{
+			RequiresType: &jwtauthnv3.JwtRequirement_ProviderName{ProviderName: name},
+		})
+	}
+	rule.RequirementType = &jwtauthnv3.RequirementRule_Requires{
+		Requires: &jwtauthnv3.JwtRequirement{
+			RequiresType: &jwtauthnv3.JwtRequirement_RequiresAny{
+				RequiresAny: &jwtauthnv3.JwtRequirementOrList{Requirements: requirements},
+			},
+		},
+	}
+
+	return rule
+}
+
+// remoteJWKSClusterName returns the CDS cluster name generated for a
+// provider's remote JWKS endpoint.
+func remoteJWKSClusterName(providerName string) string {
+	return fmt.Sprintf("jwks_%s", providerName)
+}
+
+// buildRemoteJWKSClusterForProvider parses p.RemoteJWKS.URI's host and port
+// and emits the CDS cluster backing that endpoint. Returns nil if p has no
+// RemoteJWKS (e.g. a provider type added in the future that doesn't need a
+// JWKS cluster at all).
+func buildRemoteJWKSClusterForProvider(p egv1a1.JWTProvider) (*clusterv3.Cluster, error) {
+	if p.RemoteJWKS == nil {
+		return nil, nil
+	}
+
+	u, err := url.Parse(p.RemoteJWKS.URI)
+	if err != nil {
+		return nil, fmt.Errorf("remoteJWKS.uri %q is not a valid URL: %w", p.RemoteJWKS.URI, err)
+	}
+	host := u.Hostname()
+	if host == "" {
+		return nil, fmt.Errorf("remoteJWKS.uri %q has no host", p.RemoteJWKS.URI)
+	}
+
+	port := uint32(defaultJWKSPort)
+	if portStr := u.Port(); portStr != "" {
+		parsedPort, err := net.LookupPort("tcp", portStr)
+		if err != nil {
+			return nil, fmt.Errorf("remoteJWKS.uri %q has an invalid port: %w", p.RemoteJWKS.URI, err)
+		}
+		port = uint32(parsedPort)
+	}
+
+	return buildRemoteJWKSCluster(p.Name, host, port), nil
+}
+
+// buildRemoteJWKSCluster emits the STRICT_DNS CDS cluster backing a
+// provider's remote JWKS endpoint.
+func buildRemoteJWKSCluster(providerName, host string, port uint32) *clusterv3.Cluster {
+	return &clusterv3.Cluster{
+		Name:           remoteJWKSClusterName(providerName),
+		ConnectTimeout: durationpb.New(remoteJWKSClusterConnectTimeout),
+		ClusterDiscoveryType: &clusterv3.Cluster_Type{
+			Type: clusterv3.Cluster_STRICT_DNS,
+		},
+		LoadAssignment: &endpointv3.ClusterLoadAssignment{
+			ClusterName: remoteJWKSClusterName(providerName),
+			Endpoints: []*endpointv3.LocalityLbEndpoints{{
+				LbEndpoints: []*endpointv3.LbEndpoint{{
+					HostIdentifier: &endpointv3.LbEndpoint_Endpoint{
+						Endpoint: &endpointv3.Endpoint{
+							Address: &corev3.Address{
+								Address: &corev3.Address_SocketAddress{
+									SocketAddress: &corev3.SocketAddress{
+										Address: host,
+										PortSpecifier: &corev3.SocketAddress_PortValue{
+											PortValue: port,
+										},
+									},
+								},
+							},
+						},
+					},
+				}},
+			}},
+		},
+	}
+}
+
+// insertJWTAuthnFilter inserts jwtFilter into an HCM filter chain
+// immediately before the first envoy.filters.http.rbac filter, or appends
+// it at the end if no RBAC filter is present.
+func insertJWTAuthnFilter(filters []*hcmv3.HttpFilter, jwtFilter *hcmv3.HttpFilter) []*hcmv3.HttpFilter {
+	for i, f := range filters {
+		if f.Name == rbacFilterName {
+			out := make([]*hcmv3.HttpFilter, 0, len(filters)+1)
+			out = append(out, filters[:i]...)
+			out = append(out, jwtFilter)
+			out = append(out, filters[i:]...)
+			return out
+		}
+	}
+	return append(filters, jwtFilter)
+}
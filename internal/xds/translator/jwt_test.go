@@ -0,0 +1,201 @@
+// Copyright Envoy Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"testing"
+
+	hcmv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+
+	egv1a1 "github.com/envoyproxy/gateway/api/v1alpha1"
+)
+
+func TestInsertJWTAuthnFilterOrdering(t *testing.T) {
+	rbacFilter := &hcmv3.HttpFilter{Name: rbacFilterName}
+	routerFilter := &hcmv3.HttpFilter{Name: "envoy.filters.http.router"}
+	jwtFilter := &hcmv3.HttpFilter{Name: jwtAuthnFilterName}
+
+	got := insertJWTAuthnFilter([]*hcmv3.HttpFilter{rbacFilter, routerFilter}, jwtFilter)
+
+	if len(got) != 3 {
+		t.Fatalf("expected 3 filters, got %d", len(got))
+	}
+	if got[0].Name != jwtAuthnFilterName {
+		t.Fatalf("expected jwt_authn to be inserted before rbac, got order %v", filterNames(got))
+	}
+	if got[1].Name != rbacFilterName {
+		t.Fatalf("expected rbac to remain after jwt_authn, got order %v", filterNames(got))
+	}
+}
+
+func TestInsertJWTAuthnFilterAppendsWithoutRBAC(t *testing.T) {
+	routerFilter := &hcmv3.HttpFilter{Name: "envoy.filters.http.router"}
+	jwtFilter := &hcmv3.HttpFilter{Name: jwtAuthnFilterName}
+
+	got := insertJWTAuthnFilter([]*hcmv3.HttpFilter{routerFilter}, jwtFilter)
+
+	if len(got) != 2 || got[1].Name != jwtAuthnFilterName {
+		t.Fatalf("expected jwt_authn appended at the end, got order %v", filterNames(got))
+	}
+}
+
+func filterNames(filters []*hcmv3.HttpFilter) []string {
+	names := make([]string, len(filters))
+	for i, f := range filters {
+		names[i] = f.Name
+	}
+	return names
+}
+
+func TestBuildJWTRequirementRuleDisabledOverridesProviders(t *testing.T) {
+	rule := buildJWTRequirementRule(egv1a1.JWTRequirementRule{
+		Disabled:      true,
+		ProviderNames: []string{"idp1"},
+	})
+
+	if rule.GetRequirementType() != nil {
+		t.Fatalf("expected a Disabled rule to carry no requirement, got %v", rule.GetRequirementType())
+	}
+}
+
+func TestBuildJWTRequirementRuleSingleProvider(t *testing.T) {
+	rule := buildJWTRequirementRule(egv1a1.JWTRequirementRule{ProviderNames: []string{"idp1"}})
+
+	req := rule.GetRequires()
+	if req == nil {
+		t.Fatal("expected a Requires requirement")
+	}
+	if req.GetProviderName() != "idp1" {
+		t.Fatalf("expected provider_name %q, got %q", "idp1", req.GetProviderName())
+	}
+}
+
+func TestBuildJWTRequirementRuleMultipleProviders(t *testing.T) {
+	rule := buildJWTRequirementRule(egv1a1.JWTRequirementRule{ProviderNames: []string{"idp1", "idp2"}})
+
+	req := rule.GetRequires()
+	if req == nil {
+		t.Fatal("expected the multi-provider case to still be wrapped in a Requires requirement")
+	}
+	orList := req.GetRequiresAny()
+	if orList == nil || len(orList.GetRequirements()) != 2 {
+		t.Fatalf("expected a requires_any list with 2 entries, got %v", orList)
+	}
+}
+
+func TestBuildJWTAuthnFilterEmitsRemoteJWKSClusters(t *testing.T) {
+	jwt := &egv1a1.JWTAuthentication{
+		Providers: []egv1a1.JWTProvider{{
+			Name:   "idp1",
+			Issuer: "https://idp.example.com",
+			RemoteJWKS: &egv1a1.RemoteJWKS{
+				URI: "https://idp.example.com:8443/.well-known/jwks.json",
+			},
+		}},
+	}
+
+	filter, clusters, err := buildJWTAuthnFilter(jwt)
+	if err != nil {
+		t.Fatalf("buildJWTAuthnFilter returned error: %v", err)
+	}
+	if filter.Name != jwtAuthnFilterName {
+		t.Fatalf("expected filter name %q, got %q", jwtAuthnFilterName, filter.Name)
+	}
+	if len(clusters) != 1 {
+		t.Fatalf("expected one JWKS cluster, got %d", len(clusters))
+	}
+
+	cluster := clusters[0]
+	if cluster.Name != remoteJWKSClusterName("idp1") {
+		t.Fatalf("expected cluster name %q, got %q", remoteJWKSClusterName("idp1"), cluster.Name)
+	}
+	lbEndpoint := cluster.GetLoadAssignment().GetEndpoints()[0].GetLbEndpoints()[0]
+	addr := lbEndpoint.GetEndpoint().GetAddress().GetSocketAddress()
+	if addr.GetAddress() != "idp.example.com" {
+		t.Fatalf("expected host %q, got %q", "idp.example.com", addr.GetAddress())
+	}
+	if addr.GetPortValue() != 8443 {
+		t.Fatalf("expected port 8443, got %d", addr.GetPortValue())
+	}
+}
+
+func TestBuildJWTAuthnFilterDefaultsJWKSPort(t *testing.T) {
+	jwt := &egv1a1.JWTAuthentication{
+		Providers: []egv1a1.JWTProvider{{
+			Name: "idp1",
+			RemoteJWKS: &egv1a1.RemoteJWKS{
+				URI: "https://idp.example.com/.well-known/jwks.json",
+			},
+		}},
+	}
+
+	_, clusters, err := buildJWTAuthnFilter(jwt)
+	if err != nil {
+		t.Fatalf("buildJWTAuthnFilter returned error: %v", err)
+	}
+	addr := clusters[0].GetLoadAssignment().GetEndpoints()[0].GetLbEndpoints()[0].GetEndpoint().GetAddress().GetSocketAddress()
+	if addr.GetPortValue() != 443 {
+		t.Fatalf("expected default port 443, got %d", addr.GetPortValue())
+	}
+}
+
+func TestBuildJWTProviderAsyncFetchOnlySetWhenRequested(t *testing.T) {
+	withAsync, err := buildJWTProvider(egv1a1.JWTProvider{
+		Name:       "idp1",
+		RemoteJWKS: &egv1a1.RemoteJWKS{URI: "https://idp.example.com/jwks.json", AsyncFetch: true},
+	})
+	if err != nil {
+		t.Fatalf("buildJWTProvider returned error: %v", err)
+	}
+	if withAsync.GetRemoteJwks().GetAsyncFetch() == nil {
+		t.Fatal("expected AsyncFetch to be set when RemoteJWKS.AsyncFetch is true")
+	}
+
+	withoutAsync, err := buildJWTProvider(egv1a1.JWTProvider{
+		Name:       "idp1",
+		RemoteJWKS: &egv1a1.RemoteJWKS{URI: "https://idp.example.com/jwks.json", AsyncFetch: false},
+	})
+	if err != nil {
+		t.Fatalf("buildJWTProvider returned error: %v", err)
+	}
+	if withoutAsync.GetRemoteJwks().GetAsyncFetch() != nil {
+		t.Fatal("expected AsyncFetch to stay unset when RemoteJWKS.AsyncFetch is false")
+	}
+}
+
+func TestBuildJWTProviderForwardIndependentOfForwardPayloadHeader(t *testing.T) {
+	provider, err := buildJWTProvider(egv1a1.JWTProvider{
+		Name:                 "idp1",
+		RemoteJWKS:           &egv1a1.RemoteJWKS{URI: "https://idp.example.com/jwks.json"},
+		Forward:              true,
+		ForwardPayloadHeader: "",
+	})
+	if err != nil {
+		t.Fatalf("buildJWTProvider returned error: %v", err)
+	}
+	if !provider.GetForward() {
+		t.Fatal("expected forward to be true when Forward is set, regardless of ForwardPayloadHeader")
+	}
+	if provider.GetForwardPayloadHeader() != "" {
+		t.Fatalf("expected forward_payload_header to stay unset, got %q", provider.GetForwardPayloadHeader())
+	}
+
+	provider, err = buildJWTProvider(egv1a1.JWTProvider{
+		Name:                 "idp1",
+		RemoteJWKS:           &egv1a1.RemoteJWKS{URI: "https://idp.example.com/jwks.json"},
+		Forward:              false,
+		ForwardPayloadHeader: "X-JWT-Payload",
+	})
+	if err != nil {
+		t.Fatalf("buildJWTProvider returned error: %v", err)
+	}
+	if provider.GetForward() {
+		t.Fatal("expected forward to stay false when only ForwardPayloadHeader is set")
+	}
+	if provider.GetForwardPayloadHeader() != "X-JWT-Payload" {
+		t.Fatalf("expected forward_payload_header %q, got %q", "X-JWT-Payload", provider.GetForwardPayloadHeader())
+	}
+}
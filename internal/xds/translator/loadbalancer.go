@@ -0,0 +1,231 @@
+// Copyright Envoy Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/golang/protobuf/ptypes/duration"
+
+	xdstypev3 "github.com/cncf/xds/go/xds/type/v3"
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	egv1a1 "github.com/envoyproxy/gateway/api/v1alpha1"
+)
+
+// applyLoadBalancer sets lb_policy (and, for policies that need extra
+// configuration, load_balancing_policy) on cluster from lb. For
+// ConsistentHash, the hash policies that drive Envoy's per-request hashing
+// are route, not cluster, configuration, so they're written onto route
+// instead; route may be nil for any other LoadBalancerType. Conflicting
+// combinations, like a ConsistentHash field set with Type LeastRequest, are
+// rejected rather than silently ignored.
+func applyLoadBalancer(cluster *clusterv3.Cluster, route *routev3.RouteAction, lb *egv1a1.LoadBalancer) error {
+	if lb == nil {
+		return nil
+	}
+
+	if err := validateLoadBalancer(lb); err != nil {
+		return err
+	}
+
+	switch lb.Type {
+	case egv1a1.RoundRobinLoadBalancerType, "":
+		cluster.LbPolicy = clusterv3.Cluster_ROUND_ROBIN
+	case egv1a1.RandomLoadBalancerType:
+		cluster.LbPolicy = clusterv3.Cluster_RANDOM
+	case egv1a1.LeastRequestLoadBalancerType:
+		cluster.LbPolicy = clusterv3.Cluster_LEAST_REQUEST
+		cluster.LbConfig = leastRequestLbConfig(lb.LeastRequest)
+	case egv1a1.ConsistentHashLoadBalancerType:
+		if route == nil {
+			return fmt.Errorf("loadBalancer.consistentHash requires a route action to carry the hash policy")
+		}
+		return applyConsistentHash(cluster, route, lb.ConsistentHash)
+	case egv1a1.CustomLoadBalancerType:
+		return applyCustomLoadBalancer(cluster, lb.Custom)
+	default:
+		return fmt.Errorf("unsupported load balancer type %q", lb.Type)
+	}
+
+	return nil
+}
+
+// validateLoadBalancer rejects configuration that doesn't match Type, so a
+// typo like setting ConsistentHash while Type is LeastRequest fails loudly
+// at translation time instead of being silently dropped.
+func validateLoadBalancer(lb *egv1a1.LoadBalancer) error {
+	set := map[egv1a1.LoadBalancerType]bool{
+		egv1a1.ConsistentHashLoadBalancerType: lb.ConsistentHash != nil,
+		egv1a1.LeastRequestLoadBalancerType:   lb.LeastRequest != nil,
+		egv1a1.CustomLoadBalancerType:         lb.Custom != nil,
+	}
+
+	for t, isSet := range set {
+		if isSet && lb.Type != t {
+			return fmt.Errorf("loadBalancer.%s is set but type is %q", t, lb.Type)
+		}
+	}
+
+	if lb.ConsistentHash != nil && lb.ConsistentHash.Ring != nil && lb.ConsistentHash.Maglev != nil {
+		return fmt.Errorf("loadBalancer.consistentHash: ring and maglev are mutually exclusive")
+	}
+
+	return nil
+}
+
+func leastRequestLbConfig(lr *egv1a1.LeastRequest) *clusterv3.Cluster_LeastRequestLbConfig_ {
+	cfg := &clusterv3.Cluster_LeastRequestLbConfig{}
+	if lr != nil {
+		if lr.ChoiceCount != nil {
+			cfg.ChoiceCount = wrapperspb.UInt32(*lr.ChoiceCount)
+		}
+		if lr.ActiveRequestBias != nil {
+			cfg.ActiveRequestBias = &corev3.RuntimeDouble{
+				DefaultValue: *lr.ActiveRequestBias,
+				RuntimeKey:   "upstream.least_request.active_request_bias",
+			}
+		}
+	}
+	return &clusterv3.Cluster_LeastRequestLbConfig_{LeastRequestLbConfig: cfg}
+}
+
+func applyConsistentHash(cluster *clusterv3.Cluster, route *routev3.RouteAction, ch *egv1a1.ConsistentHash) error {
+	if ch == nil {
+		return fmt.Errorf("loadBalancer.consistentHash must be set when type is ConsistentHash")
+	}
+
+	hashPolicies, err := buildHashPolicies(ch.HashPolicies)
+	if err != nil {
+		return err
+	}
+	route.HashPolicy = hashPolicies
+
+	switch {
+	case ch.Maglev != nil:
+		cluster.LbPolicy = clusterv3.Cluster_MAGLEV
+		cfg := &clusterv3.Cluster_MaglevLbConfig{}
+		if ch.Maglev.TableSize > 0 {
+			cfg.TableSize = wrapperspb.UInt64(ch.Maglev.TableSize)
+		}
+		cluster.LbConfig = &clusterv3.Cluster_MaglevLbConfig_{MaglevLbConfig: cfg}
+	default:
+		cluster.LbPolicy = clusterv3.Cluster_RING_HASH
+		cfg := &clusterv3.Cluster_RingHashLbConfig{}
+		if ch.Ring != nil {
+			if ch.Ring.MinimumRingSize > 0 {
+				cfg.MinimumRingSize = wrapperspb.UInt64(ch.Ring.MinimumRingSize)
+			}
+			if ch.Ring.MaximumRingSize > 0 {
+				cfg.MaximumRingSize = wrapperspb.UInt64(ch.Ring.MaximumRingSize)
+			}
+		}
+		cluster.LbConfig = &clusterv3.Cluster_RingHashLbConfig_{RingHashLbConfig: cfg}
+	}
+
+	return nil
+}
+
+func buildHashPolicies(policies []egv1a1.HashPolicy) ([]*routev3.RouteAction_HashPolicy, error) {
+	out := make([]*routev3.RouteAction_HashPolicy, 0, len(policies))
+	for _, p := range policies {
+		switch p.Type {
+		case egv1a1.HeaderHashPolicyType:
+			if p.Header == nil {
+				return nil, fmt.Errorf("hashPolicy.header must be set when type is Header")
+			}
+			out = append(out, &routev3.RouteAction_HashPolicy{
+				PolicySpecifier: &routev3.RouteAction_HashPolicy_Header_{
+					Header: &routev3.RouteAction_HashPolicy_Header{HeaderName: *p.Header},
+				},
+			})
+		case egv1a1.CookieHashPolicyType:
+			if p.Cookie == nil {
+				return nil, fmt.Errorf("hashPolicy.cookie must be set when type is Cookie")
+			}
+			out = append(out, &routev3.RouteAction_HashPolicy{
+				PolicySpecifier: &routev3.RouteAction_HashPolicy_Cookie_{
+					Cookie: &routev3.RouteAction_HashPolicy_Cookie{
+						Name: p.Cookie.Name,
+						Path: p.Cookie.Path,
+						Ttl:  durationFromProto(&p.Cookie.TTL),
+					},
+				},
+			})
+		case egv1a1.SourceIPHashPolicyType:
+			out = append(out, &routev3.RouteAction_HashPolicy{
+				PolicySpecifier: &routev3.RouteAction_HashPolicy_ConnectionProperties_{
+					ConnectionProperties: &routev3.RouteAction_HashPolicy_ConnectionProperties{SourceIp: true},
+				},
+			})
+		case egv1a1.QueryParameterHashPolicyType:
+			if p.QueryParameter == nil {
+				return nil, fmt.Errorf("hashPolicy.queryParameter must be set when type is QueryParameter")
+			}
+			out = append(out, &routev3.RouteAction_HashPolicy{
+				PolicySpecifier: &routev3.RouteAction_HashPolicy_QueryParameter_{
+					QueryParameter: &routev3.RouteAction_HashPolicy_QueryParameter{Name: *p.QueryParameter},
+				},
+			})
+		default:
+			return nil, fmt.Errorf("unsupported hash policy type %q", p.Type)
+		}
+	}
+	return out, nil
+}
+
+// applyCustomLoadBalancer wires an out-of-tree Envoy load balancer policy
+// through Cluster.load_balancing_policy. lb_policy must be set to
+// LOAD_BALANCING_POLICY_CONFIG, or Envoy ignores load_balancing_policy and
+// falls back to the zero-value ROUND_ROBIN.
+func applyCustomLoadBalancer(cluster *clusterv3.Cluster, custom *egv1a1.CustomLoadBalancer) error {
+	if custom == nil || custom.Name == "" {
+		return fmt.Errorf("loadBalancer.custom.name must be set when type is Custom")
+	}
+
+	val := &structpb.Struct{}
+	if len(custom.TypedConfig.Raw) > 0 {
+		if err := json.Unmarshal(custom.TypedConfig.Raw, val); err != nil {
+			return fmt.Errorf("loadBalancer.custom.typedConfig is not a valid JSON object: %w", err)
+		}
+	}
+
+	typedStruct := &xdstypev3.TypedStruct{
+		TypeUrl: custom.Name,
+		Value:   val,
+	}
+	any, err := anypb.New(typedStruct)
+	if err != nil {
+		return fmt.Errorf("failed to marshal custom load balancer config: %w", err)
+	}
+
+	cluster.LbPolicy = clusterv3.Cluster_LOAD_BALANCING_POLICY_CONFIG
+	cluster.LoadBalancingPolicy = &clusterv3.LoadBalancingPolicy{
+		Policies: []*clusterv3.LoadBalancingPolicy_Policy{{
+			TypedExtensionConfig: &corev3.TypedExtensionConfig{
+				Name:        custom.Name,
+				TypedConfig: any,
+			},
+		}},
+	}
+
+	return nil
+}
+
+// durationFromProto converts the legacy golang/protobuf duration used by the
+// v1alpha1 API into the durationpb type Envoy's route proto expects. d is
+// taken by pointer, rather than by value, to avoid copying the embedded
+// proto message lock that go vet flags on the legacy duration type.
+func durationFromProto(d *duration.Duration) *durationpb.Duration {
+	return &durationpb.Duration{Seconds: d.GetSeconds(), Nanos: d.GetNanos()}
+}
@@ -0,0 +1,111 @@
+// Copyright Envoy Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"testing"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+
+	egv1a1 "github.com/envoyproxy/gateway/api/v1alpha1"
+)
+
+func TestApplyLoadBalancerConsistentHash(t *testing.T) {
+	cluster := &clusterv3.Cluster{}
+	route := &routev3.RouteAction{}
+	header := "x-user-id"
+
+	lb := &egv1a1.LoadBalancer{
+		Type: egv1a1.ConsistentHashLoadBalancerType,
+		ConsistentHash: &egv1a1.ConsistentHash{
+			HashPolicies: []egv1a1.HashPolicy{{
+				Type:   egv1a1.HeaderHashPolicyType,
+				Header: &header,
+			}},
+		},
+	}
+
+	if err := applyLoadBalancer(cluster, route, lb); err != nil {
+		t.Fatalf("applyLoadBalancer returned error: %v", err)
+	}
+
+	if cluster.LbPolicy != clusterv3.Cluster_RING_HASH {
+		t.Fatalf("expected RING_HASH lb_policy, got %v", cluster.LbPolicy)
+	}
+	if len(route.HashPolicy) != 1 {
+		t.Fatalf("expected hash policy to be set on the route action, got %d entries", len(route.HashPolicy))
+	}
+	if got := route.HashPolicy[0].GetHeader().GetHeaderName(); got != header {
+		t.Fatalf("expected header hash policy on %q, got %q", header, got)
+	}
+}
+
+func TestApplyLoadBalancerConsistentHashRequiresRoute(t *testing.T) {
+	cluster := &clusterv3.Cluster{}
+	lb := &egv1a1.LoadBalancer{
+		Type:           egv1a1.ConsistentHashLoadBalancerType,
+		ConsistentHash: &egv1a1.ConsistentHash{},
+	}
+
+	if err := applyLoadBalancer(cluster, nil, lb); err == nil {
+		t.Fatal("expected an error when no route action is available to carry the hash policy")
+	}
+}
+
+func TestApplyLoadBalancerLeastRequest(t *testing.T) {
+	cluster := &clusterv3.Cluster{}
+	choiceCount := uint32(3)
+
+	lb := &egv1a1.LoadBalancer{
+		Type:         egv1a1.LeastRequestLoadBalancerType,
+		LeastRequest: &egv1a1.LeastRequest{ChoiceCount: &choiceCount},
+	}
+
+	if err := applyLoadBalancer(cluster, nil, lb); err != nil {
+		t.Fatalf("applyLoadBalancer returned error: %v", err)
+	}
+	if cluster.LbPolicy != clusterv3.Cluster_LEAST_REQUEST {
+		t.Fatalf("expected LEAST_REQUEST lb_policy, got %v", cluster.LbPolicy)
+	}
+	cfg := cluster.GetLeastRequestLbConfig()
+	if cfg.GetChoiceCount().GetValue() != choiceCount {
+		t.Fatalf("expected choice_count %d, got %d", choiceCount, cfg.GetChoiceCount().GetValue())
+	}
+}
+
+func TestApplyLoadBalancerRejectsMismatchedType(t *testing.T) {
+	cluster := &clusterv3.Cluster{}
+	lb := &egv1a1.LoadBalancer{
+		Type:           egv1a1.RoundRobinLoadBalancerType,
+		ConsistentHash: &egv1a1.ConsistentHash{},
+	}
+
+	if err := applyLoadBalancer(cluster, nil, lb); err == nil {
+		t.Fatal("expected an error when ConsistentHash is set but Type is RoundRobin")
+	}
+}
+
+func TestApplyCustomLoadBalancer(t *testing.T) {
+	cluster := &clusterv3.Cluster{}
+	lb := &egv1a1.LoadBalancer{
+		Type:   egv1a1.CustomLoadBalancerType,
+		Custom: &egv1a1.CustomLoadBalancer{Name: "envoy.load_balancing_policies.wrr_locality"},
+	}
+
+	if err := applyLoadBalancer(cluster, nil, lb); err != nil {
+		t.Fatalf("applyLoadBalancer returned error: %v", err)
+	}
+	if len(cluster.GetLoadBalancingPolicy().GetPolicies()) != 1 {
+		t.Fatalf("expected one load_balancing_policy entry, got %d", len(cluster.GetLoadBalancingPolicy().GetPolicies()))
+	}
+	if name := cluster.GetLoadBalancingPolicy().GetPolicies()[0].GetTypedExtensionConfig().GetName(); name != lb.Custom.Name {
+		t.Fatalf("expected typed_extension_config name %q, got %q", lb.Custom.Name, name)
+	}
+	if cluster.GetLbPolicy() != clusterv3.Cluster_LOAD_BALANCING_POLICY_CONFIG {
+		t.Fatalf("expected lb_policy LOAD_BALANCING_POLICY_CONFIG, got %v", cluster.GetLbPolicy())
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright Envoy Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	matcherv3 "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
+
+	egv1a1 "github.com/envoyproxy/gateway/api/v1alpha1"
+)
+
+// stringMatchToMatcher converts a StringMatch into Envoy's generic
+// StringMatcher, used by principals and permissions alike. An unset
+// StringMatch matches nothing specific and falls back to a "" exact match.
+func stringMatchToMatcher(m egv1a1.StringMatch) *matcherv3.StringMatcher {
+	switch {
+	case m.Exact != nil:
+		return &matcherv3.StringMatcher{MatchPattern: &matcherv3.StringMatcher_Exact{Exact: *m.Exact}}
+	case m.Prefix != nil:
+		return &matcherv3.StringMatcher{MatchPattern: &matcherv3.StringMatcher_Prefix{Prefix: *m.Prefix}}
+	case m.Suffix != nil:
+		return &matcherv3.StringMatcher{MatchPattern: &matcherv3.StringMatcher_Suffix{Suffix: *m.Suffix}}
+	case m.Regex != nil:
+		return &matcherv3.StringMatcher{
+			MatchPattern: &matcherv3.StringMatcher_SafeRegex{
+				SafeRegex: &matcherv3.RegexMatcher{Regex: *m.Regex},
+			},
+		}
+	default:
+		return &matcherv3.StringMatcher{MatchPattern: &matcherv3.StringMatcher_Exact{Exact: ""}}
+	}
+}
+
+// stringMatchToValueMatcher converts a StringMatch into the ValueMatcher
+// used by metadata-based matchers, e.g. for JWT claim comparisons.
+func stringMatchToValueMatcher(m egv1a1.StringMatch) *matcherv3.ValueMatcher {
+	return &matcherv3.ValueMatcher{
+		MatchPattern: &matcherv3.ValueMatcher_StringMatch{StringMatch: stringMatchToMatcher(m)},
+	}
+}
+
+// metadataPath converts a claim path, one key per element, into Envoy's
+// repeated MetadataMatcher.PathSegment form. Elements are not split on dots.
+func metadataPath(path []string) []*matcherv3.MetadataMatcher_PathSegment {
+	out := make([]*matcherv3.MetadataMatcher_PathSegment, 0, len(path))
+	for _, seg := range path {
+		out = append(out, &matcherv3.MetadataMatcher_PathSegment{
+			Segment: &matcherv3.MetadataMatcher_PathSegment_Key{Key: seg},
+		})
+	}
+	return out
+}
+
+// headerMatcher converts a header name/value match into Envoy's
+// HeaderMatcher, used by RBAC header principals.
+func headerMatcher(name string, value egv1a1.StringMatch) *routev3.HeaderMatcher {
+	return &routev3.HeaderMatcher{
+		Name:                 name,
+		HeaderMatchSpecifier: &routev3.HeaderMatcher_StringMatch{StringMatch: stringMatchToMatcher(value)},
+	}
+}
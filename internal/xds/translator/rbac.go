@@ -0,0 +1,178 @@
+// Copyright Envoy Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"fmt"
+
+	rbacconfigv3 "github.com/envoyproxy/go-control-plane/envoy/config/rbac/v3"
+	rbacv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/rbac/v3"
+	hcmv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	matcherv3 "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	egv1a1 "github.com/envoyproxy/gateway/api/v1alpha1"
+)
+
+const (
+	// shadowStatPrefixPrefix is prepended to a ShadowAuthorization's
+	// StatPrefix (or policy name, when unset) to form the full Envoy
+	// shadow_rules_stat_prefix, and the corresponding stats matcher prefix.
+	shadowStatPrefixPrefix = "rbac.shadow."
+)
+
+// buildRBACFilters translates an Authorization policy into a single
+// envoy.filters.http.rbac HTTP filter. Envoy's RBAC filter has no SHADOW
+// action; shadow evaluation is expressed by setting ShadowRules (evaluated
+// and counted, but never enforced) alongside the enforcing Rules on the
+// same filter instance, not by running a second filter with its own
+// ALLOW/DENY action, which would enforce the "shadow" policy too.
+func buildRBACFilters(policyName string, authz *egv1a1.Authorization) ([]*hcmv3.HttpFilter, error) {
+	if authz == nil || len(authz.Rules) == 0 {
+		return nil, fmt.Errorf("authorization must define at least one rule")
+	}
+
+	enforcePolicy, err := buildRBACPolicy(authz.Rules)
+	if err != nil {
+		return nil, fmt.Errorf("rules: %w", err)
+	}
+
+	action := rbacconfigv3.RBAC_ALLOW
+	if authz.Action == egv1a1.AuthorizationActionDeny {
+		action = rbacconfigv3.RBAC_DENY
+	}
+
+	cfg := &rbacv3.RBAC{
+		Rules: &rbacconfigv3.RBAC{
+			Action:   action,
+			Policies: map[string]*rbacconfigv3.Policy{policyName: enforcePolicy},
+		},
+	}
+
+	if authz.Shadow != nil {
+		shadowRules := authz.Shadow.Rules
+		if len(shadowRules) == 0 {
+			shadowRules = authz.Rules
+		}
+		shadowPolicy, err := buildRBACPolicy(shadowRules)
+		if err != nil {
+			return nil, fmt.Errorf("shadow.rules: %w", err)
+		}
+
+		cfg.ShadowRules = &rbacconfigv3.RBAC{
+			Action:   action,
+			Policies: map[string]*rbacconfigv3.Policy{policyName: shadowPolicy},
+		}
+		cfg.ShadowRulesStatPrefix = shadowStatPrefix(policyName, authz.Shadow)
+	}
+
+	filter, err := rbacHTTPFilter(rbacFilterName, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return []*hcmv3.HttpFilter{filter}, nil
+}
+
+// ShadowRBACStatPrefixes returns the Envoy stats matcher inclusion prefixes
+// needed to scrape the shadow-allowed/shadow-denied counters for every
+// policy that sets Shadow. Pass the result as the additionalStatsPrefixes
+// argument to bootstrap.GetRenderedBootstrapConfig.
+func ShadowRBACStatPrefixes(policies map[string]*egv1a1.Authorization) []string {
+	var prefixes []string
+	for name, authz := range policies {
+		if authz == nil || authz.Shadow == nil {
+			continue
+		}
+		prefixes = append(prefixes, shadowStatPrefix(name, authz.Shadow))
+	}
+	return prefixes
+}
+
+func shadowStatPrefix(policyName string, shadow *egv1a1.ShadowAuthorization) string {
+	prefix := shadow.StatPrefix
+	if prefix == "" {
+		prefix = policyName
+	}
+	return shadowStatPrefixPrefix + prefix
+}
+
+func rbacHTTPFilter(name string, cfg *rbacv3.RBAC) (*hcmv3.HttpFilter, error) {
+	any, err := anypb.New(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal rbac config: %w", err)
+	}
+	return &hcmv3.HttpFilter{
+		Name:       name,
+		ConfigType: &hcmv3.HttpFilter_TypedConfig{TypedConfig: any},
+	}, nil
+}
+
+func buildRBACPolicy(rules []egv1a1.AuthorizationRule) (*rbacconfigv3.Policy, error) {
+	policy := &rbacconfigv3.Policy{}
+	for _, rule := range rules {
+		principals, err := buildPrincipals(rule.Principals)
+		if err != nil {
+			return nil, fmt.Errorf("rule %q: %w", rule.Name, err)
+		}
+		policy.Principals = append(policy.Principals, principals...)
+	}
+	if len(policy.Principals) == 0 {
+		return nil, fmt.Errorf("no principals matched any rule")
+	}
+	// Permissions are unconditional; matching is driven entirely by
+	// Principals, consistent with how Rules are scoped to a route/listener
+	// upstream of the RBAC filter.
+	policy.Permissions = []*rbacconfigv3.Permission{{
+		Rule: &rbacconfigv3.Permission_Any{Any: true},
+	}}
+	return policy, nil
+}
+
+func buildPrincipals(principals []egv1a1.Principal) ([]*rbacconfigv3.Principal, error) {
+	out := make([]*rbacconfigv3.Principal, 0, len(principals))
+	for _, p := range principals {
+		switch {
+		case p.JWTMetadata != nil:
+			out = append(out, &rbacconfigv3.Principal{
+				Identifier: &rbacconfigv3.Principal_Metadata{
+					Metadata: &matcherv3.MetadataMatcher{
+						Filter: p.JWTMetadata.Filter,
+						Path:   metadataPath(p.JWTMetadata.Path),
+						Value:  stringMatchToValueMatcher(p.JWTMetadata.Value),
+					},
+				},
+			})
+		case p.URLPath != nil:
+			out = append(out, &rbacconfigv3.Principal{
+				Identifier: &rbacconfigv3.Principal_UrlPath{
+					UrlPath: &matcherv3.PathMatcher{
+						Rule: &matcherv3.PathMatcher_Path{
+							Path: stringMatchToMatcher(*p.URLPath),
+						},
+					},
+				},
+			})
+		case p.Header != nil:
+			out = append(out, &rbacconfigv3.Principal{
+				Identifier: &rbacconfigv3.Principal_Header{
+					Header: headerMatcher(p.Header.Name, p.Header.Value),
+				},
+			})
+		case p.ConnectionSAN != nil:
+			out = append(out, &rbacconfigv3.Principal{
+				Identifier: &rbacconfigv3.Principal_Authenticated_{
+					Authenticated: &rbacconfigv3.Principal_Authenticated{
+						PrincipalName: stringMatchToMatcher(*p.ConnectionSAN),
+					},
+				},
+			})
+		default:
+			return nil, fmt.Errorf("principal must set exactly one of jwtMetadata, urlPath, header, connectionSAN")
+		}
+	}
+	return out, nil
+}
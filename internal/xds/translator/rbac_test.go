@@ -0,0 +1,106 @@
+// Copyright Envoy Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"testing"
+
+	rbacconfigv3 "github.com/envoyproxy/go-control-plane/envoy/config/rbac/v3"
+	rbacv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/http/rbac/v3"
+	hcmv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/filters/network/http_connection_manager/v3"
+	"google.golang.org/protobuf/proto"
+
+	egv1a1 "github.com/envoyproxy/gateway/api/v1alpha1"
+)
+
+func exactPath(p string) *egv1a1.StringMatch {
+	return &egv1a1.StringMatch{Exact: &p}
+}
+
+func TestBuildRBACFiltersEnforceOnly(t *testing.T) {
+	authz := &egv1a1.Authorization{
+		Action: egv1a1.AuthorizationActionAllow,
+		Rules: []egv1a1.AuthorizationRule{{
+			Name:       "allow-health",
+			Principals: []egv1a1.Principal{{URLPath: exactPath("/healthz")}},
+		}},
+	}
+
+	filters, err := buildRBACFilters("policy1", authz)
+	if err != nil {
+		t.Fatalf("buildRBACFilters returned error: %v", err)
+	}
+	if len(filters) != 1 {
+		t.Fatalf("expected a single RBAC filter, got %d", len(filters))
+	}
+
+	cfg := decodeRBACConfig(t, filters[0])
+	if cfg.GetRules().GetAction() != rbacconfigv3.RBAC_ALLOW {
+		t.Fatalf("expected ALLOW action, got %v", cfg.GetRules().GetAction())
+	}
+	if cfg.GetShadowRules() != nil {
+		t.Fatalf("expected no shadow_rules when Shadow is unset, got %v", cfg.GetShadowRules())
+	}
+}
+
+func TestBuildRBACFiltersWithShadow(t *testing.T) {
+	authz := &egv1a1.Authorization{
+		Action: egv1a1.AuthorizationActionDeny,
+		Rules: []egv1a1.AuthorizationRule{{
+			Name:       "deny-admin",
+			Principals: []egv1a1.Principal{{URLPath: exactPath("/admin")}},
+		}},
+		Shadow: &egv1a1.ShadowAuthorization{
+			Rules: []egv1a1.AuthorizationRule{{
+				Name:       "deny-admin-v2",
+				Principals: []egv1a1.Principal{{URLPath: exactPath("/admin/v2")}},
+			}},
+			StatPrefix: "admin-rollout",
+		},
+	}
+
+	filters, err := buildRBACFilters("policy1", authz)
+	if err != nil {
+		t.Fatalf("buildRBACFilters returned error: %v", err)
+	}
+	if len(filters) != 1 {
+		t.Fatalf("expected shadow evaluation to stay on a single filter instance, got %d filters", len(filters))
+	}
+
+	cfg := decodeRBACConfig(t, filters[0])
+
+	if cfg.GetRules().GetAction() != rbacconfigv3.RBAC_DENY {
+		t.Fatalf("expected enforcing action DENY, got %v", cfg.GetRules().GetAction())
+	}
+	if cfg.GetShadowRules() == nil {
+		t.Fatal("expected shadow_rules to be populated")
+	}
+	if cfg.GetShadowRulesStatPrefix() != "rbac.shadow.admin-rollout" {
+		t.Fatalf("expected shadow_rules_stat_prefix %q, got %q", "rbac.shadow.admin-rollout", cfg.GetShadowRulesStatPrefix())
+	}
+	// The enforcing policy must still be the one actually blocking traffic;
+	// the shadow policy must never appear under Rules.
+	enforced, ok := cfg.GetRules().GetPolicies()["policy1"]
+	if !ok {
+		t.Fatal("expected enforcing policy to be present under Rules")
+	}
+	if got := enforced.GetPrincipals()[0].GetUrlPath().GetPath().GetExact(); got != "/admin" {
+		t.Fatalf("expected Rules to carry the enforcing /admin principal, not the shadow one, got %q", got)
+	}
+}
+
+func decodeRBACConfig(t *testing.T, filter *hcmv3.HttpFilter) *rbacv3.RBAC {
+	t.Helper()
+	typedConfig, ok := filter.GetConfigType().(*hcmv3.HttpFilter_TypedConfig)
+	if !ok {
+		t.Fatalf("expected a typed config, got %T", filter.GetConfigType())
+	}
+	cfg := &rbacv3.RBAC{}
+	if err := proto.Unmarshal(typedConfig.TypedConfig.GetValue(), cfg); err != nil {
+		t.Fatalf("failed to unmarshal rbac config: %v", err)
+	}
+	return cfg
+}
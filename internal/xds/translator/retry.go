@@ -0,0 +1,308 @@
+// Copyright Envoy Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"fmt"
+	"strconv"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	omitcanaryhostsv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/retry/host/omit_canary_hosts/v3"
+	omithostmetadatav3 "github.com/envoyproxy/go-control-plane/envoy/extensions/retry/host/omit_host_metadata/v3"
+	prevpriov3 "github.com/envoyproxy/go-control-plane/envoy/extensions/retry/priority/previous_priorities/v3"
+	typev3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	egv1a1 "github.com/envoyproxy/gateway/api/v1alpha1"
+)
+
+const (
+	// previousPrioritiesRetryPriorityName is the Envoy built-in retry
+	// priority that de-prioritizes host priority levels previous retries in
+	// the same request failed against.
+	previousPrioritiesRetryPriorityName = "envoy.retry_priorities.previous_priorities"
+	// previousHostsRetryHostPredicateName excludes hosts the request has
+	// already been sent to during this retry sequence.
+	previousHostsRetryHostPredicateName = "envoy.retry_host_predicates.previous_hosts"
+	// omitCanaryHostsRetryHostPredicateName excludes hosts marked as
+	// canaries via the "envoy.lb"/"canary" host metadata key.
+	omitCanaryHostsRetryHostPredicateName = "envoy.retry_host_predicates.omit_canary_hosts"
+	// omitHostMetadataRetryHostPredicateName excludes hosts matching
+	// caller-supplied metadata from being retried against.
+	omitHostMetadataRetryHostPredicateName = "envoy.retry_host_predicates.omit_host_metadata"
+	// lbMetadataNamespace is the reverse-DNS metadata namespace Envoy's
+	// built-in load balancing and retry host predicates read host metadata
+	// from.
+	lbMetadataNamespace = "envoy.lb"
+)
+
+// buildRetryPolicy translates a RetryStrategy into the Envoy RetryPolicy
+// carried on a route's RouteAction. Http and Grpc strategies are supported;
+// Grpc only sets retry_on from GrpcRetry.RetryOn, and otherwise shares the
+// same per-try handling. RetryLimit is not applied here; applyRetryLimit
+// maps it onto the cluster.
+func buildRetryPolicy(rs *egv1a1.RetryStrategy) (*routev3.RetryPolicy, error) {
+	if rs == nil {
+		return nil, nil
+	}
+
+	rp := &routev3.RetryPolicy{}
+
+	switch rs.Type {
+	case egv1a1.ProtocolTypeGrpc:
+		if rs.Grpc == nil {
+			return nil, fmt.Errorf("retryStrategy.grpc must be set when type is Grpc")
+		}
+		rp.RetryOn = string(rs.Grpc.RetryOn)
+	case egv1a1.ProtocolTypeHttp, "":
+		if rs.Http == nil {
+			return nil, fmt.Errorf("retryStrategy.http must be set when type is Http")
+		}
+		if err := applyHTTPRetry(rp, rs.Http); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported retry strategy type %q", rs.Type)
+	}
+
+	if rs.NumRetries > 0 {
+		rp.NumRetries = wrapperspb.UInt32(uint32(rs.NumRetries))
+	}
+
+	applyPerRetryPolicy(rp, &rs.PerRetry)
+
+	if rs.Http != nil && rs.Http.HedgedRetry != nil {
+		applyHedgeJitter(rp, rs.Http.HedgedRetry)
+	}
+
+	return rp, nil
+}
+
+func applyHTTPRetry(rp *routev3.RetryPolicy, http *egv1a1.HttpRetry) error {
+	rp.RetryOn = string(http.RetryOn)
+	for _, code := range http.RetriableStatusCodes {
+		rp.RetriableStatusCodes = append(rp.RetriableStatusCodes, uint32(code))
+	}
+
+	if http.RetryPriority != nil {
+		updateFrequency := int32(2)
+		if http.RetryPriority.UpdateFrequency > 0 {
+			updateFrequency = int32(http.RetryPriority.UpdateFrequency)
+		}
+		any, err := anypb.New(&prevpriov3.PreviousPrioritiesConfig{UpdateFrequency: updateFrequency})
+		if err != nil {
+			return fmt.Errorf("failed to marshal previous_priorities config: %w", err)
+		}
+		rp.RetryPriority = &routev3.RetryPolicy_RetryPriority{
+			Name: previousPrioritiesRetryPriorityName,
+			ConfigType: &routev3.RetryPolicy_RetryPriority_TypedConfig{
+				TypedConfig: any,
+			},
+		}
+	}
+
+	if hp := http.RetryHostPredicate; hp != nil {
+		if hp.PreviousHosts {
+			rp.RetryHostPredicate = append(rp.RetryHostPredicate, &routev3.RetryPolicy_RetryHostPredicate{
+				Name: previousHostsRetryHostPredicateName,
+			})
+		}
+		if hp.OmitCanaryHosts {
+			any, err := anypb.New(&omitcanaryhostsv3.OmitCanaryHostsPredicate{})
+			if err != nil {
+				return fmt.Errorf("failed to marshal omit_canary_hosts config: %w", err)
+			}
+			rp.RetryHostPredicate = append(rp.RetryHostPredicate, &routev3.RetryPolicy_RetryHostPredicate{
+				Name: omitCanaryHostsRetryHostPredicateName,
+				ConfigType: &routev3.RetryPolicy_RetryHostPredicate_TypedConfig{
+					TypedConfig: any,
+				},
+			})
+		}
+		if len(hp.OmitHostMetadata) > 0 {
+			fields := make(map[string]*structpb.Value, len(hp.OmitHostMetadata))
+			for k, v := range hp.OmitHostMetadata {
+				fields[k] = structpb.NewStringValue(v)
+			}
+			any, err := anypb.New(&omithostmetadatav3.OmitHostMetadataConfig{
+				MetadataMatch: &corev3.Metadata{
+					FilterMetadata: map[string]*structpb.Struct{
+						lbMetadataNamespace: {Fields: fields},
+					},
+				},
+			})
+			if err != nil {
+				return fmt.Errorf("failed to marshal omit_host_metadata config: %w", err)
+			}
+			rp.RetryHostPredicate = append(rp.RetryHostPredicate, &routev3.RetryPolicy_RetryHostPredicate{
+				Name: omitHostMetadataRetryHostPredicateName,
+				ConfigType: &routev3.RetryPolicy_RetryHostPredicate_TypedConfig{
+					TypedConfig: any,
+				},
+			})
+		}
+	}
+
+	if lb := http.RateLimitedBackOff; lb != nil {
+		if len(lb.ResetHeaders) == 0 {
+			return fmt.Errorf("retryStrategy.http.rateLimitedBackOff.resetHeaders must list at least one header")
+		}
+
+		rp.RateLimitedRetryBackOff = &routev3.RetryPolicy_RateLimitedRetryBackOff{}
+		if lb.MaxInterval.Seconds > 0 || lb.MaxInterval.Nanos > 0 {
+			rp.RateLimitedRetryBackOff.MaxInterval = durationFromProto(&lb.MaxInterval)
+		}
+		for _, h := range lb.ResetHeaders {
+			format := routev3.RetryPolicy_SECONDS
+			if h.Format == egv1a1.ResetHeaderFormatUnixTimestamp {
+				format = routev3.RetryPolicy_UNIX_TIMESTAMP
+			}
+			rp.RateLimitedRetryBackOff.ResetHeaders = append(rp.RateLimitedRetryBackOff.ResetHeaders, &routev3.RetryPolicy_ResetHeader{
+				Name:   h.Name,
+				Format: format,
+			})
+		}
+	}
+
+	return nil
+}
+
+func applyPerRetryPolicy(rp *routev3.RetryPolicy, pr *egv1a1.PerRetryPolicy) {
+	if pr.Timeout.Seconds > 0 || pr.Timeout.Nanos > 0 {
+		rp.PerTryTimeout = durationFromProto(&pr.Timeout)
+	}
+	if pr.IdleTimeout.Seconds > 0 || pr.IdleTimeout.Nanos > 0 {
+		rp.PerTryIdleTimeout = durationFromProto(&pr.IdleTimeout)
+	}
+	if pr.BackOff.BaseInterval.Seconds > 0 || pr.BackOff.BaseInterval.Nanos > 0 {
+		rp.RetryBackOff = &routev3.RetryPolicy_RetryBackOff{
+			BaseInterval: durationFromProto(&pr.BackOff.BaseInterval),
+		}
+		if pr.BackOff.MaxInterval.Seconds > 0 || pr.BackOff.MaxInterval.Nanos > 0 {
+			rp.RetryBackOff.MaxInterval = durationFromProto(&pr.BackOff.MaxInterval)
+		}
+	}
+}
+
+// buildHedgePolicy translates HedgedRetry into the HedgePolicy carried
+// alongside RetryPolicy on a route's RouteAction. HedgePolicy itself has no
+// notion of jitter, so InitialJitter/MaxJitter are applied to the sibling
+// RetryPolicy's back-off by applyHedgeJitter instead of being dropped.
+func buildHedgePolicy(h *egv1a1.HedgedRetry) *routev3.HedgePolicy {
+	if h == nil {
+		return nil
+	}
+	return &routev3.HedgePolicy{
+		HedgeOnPerTryTimeout: h.HedgeOnPerTryTimeout,
+	}
+}
+
+// applyHedgeJitter maps HedgedRetry.InitialJitter/MaxJitter onto rp's
+// RetryBackOff, since Envoy has no dedicated hedge jitter field and the
+// back-off interval is what actually governs the delay before a hedged
+// retry fires. Explicit jitter values take precedence over whatever
+// PerRetryPolicy.BackOff already set.
+func applyHedgeJitter(rp *routev3.RetryPolicy, h *egv1a1.HedgedRetry) {
+	hasInitial := h.InitialJitter.Seconds > 0 || h.InitialJitter.Nanos > 0
+	hasMax := h.MaxJitter.Seconds > 0 || h.MaxJitter.Nanos > 0
+	if !hasInitial && !hasMax {
+		return
+	}
+
+	if rp.RetryBackOff == nil {
+		rp.RetryBackOff = &routev3.RetryPolicy_RetryBackOff{}
+	}
+	if hasInitial {
+		rp.RetryBackOff.BaseInterval = durationFromProto(&h.InitialJitter)
+	}
+	if hasMax {
+		rp.RetryBackOff.MaxInterval = durationFromProto(&h.MaxJitter)
+	}
+}
+
+// applyRetryBudget maps a RetryLimitPolicy configured with the RetryBudget
+// type onto cluster's circuit breaker thresholds: Envoy enforces the retry
+// concurrency limit there, not on the route, and a configured retry_budget
+// overrides any max_retries circuit breaker on the same threshold.
+func applyRetryBudget(cluster *clusterv3.Cluster, limit *egv1a1.RetryLimitPolicy) error {
+	if limit == nil || limit.Type != egv1a1.RetryLimitTypeRetryBudget {
+		return nil
+	}
+
+	budget := &clusterv3.CircuitBreakers_Thresholds_RetryBudget{}
+	if limit.RetryBudget.ActiveRequestPercent > 0 {
+		budget.BudgetPercent = &typev3.Percent{Value: float64(limit.RetryBudget.ActiveRequestPercent)}
+	}
+	if limit.RetryBudget.MinConcurrent > 0 {
+		budget.MinRetryConcurrency = wrapperspb.UInt32(uint32(limit.RetryBudget.MinConcurrent))
+	}
+
+	if cluster.CircuitBreakers == nil {
+		cluster.CircuitBreakers = &clusterv3.CircuitBreakers{}
+	}
+	for _, t := range cluster.CircuitBreakers.Thresholds {
+		if t.Priority == corev3.RoutingPriority_DEFAULT {
+			t.RetryBudget = budget
+			return nil
+		}
+	}
+	cluster.CircuitBreakers.Thresholds = append(cluster.CircuitBreakers.Thresholds, &clusterv3.CircuitBreakers_Thresholds{
+		Priority:    corev3.RoutingPriority_DEFAULT,
+		RetryBudget: budget,
+	})
+
+	return nil
+}
+
+// applyStaticRetryLimit maps a RetryLimitPolicy configured with the Static
+// type onto cluster's circuit breaker max_retries threshold.
+func applyStaticRetryLimit(cluster *clusterv3.Cluster, limit *egv1a1.RetryLimitPolicy) error {
+	if limit == nil || limit.Type != egv1a1.RetryLimitTypeStatic {
+		return nil
+	}
+	if limit.Static.MaxParallel <= 0 {
+		return fmt.Errorf("retryLimit.static.maxParallel must be greater than zero, got %s", strconv.Itoa(limit.Static.MaxParallel))
+	}
+
+	if cluster.CircuitBreakers == nil {
+		cluster.CircuitBreakers = &clusterv3.CircuitBreakers{}
+	}
+	for _, t := range cluster.CircuitBreakers.Thresholds {
+		if t.Priority == corev3.RoutingPriority_DEFAULT {
+			t.MaxRetries = wrapperspb.UInt32(uint32(limit.Static.MaxParallel))
+			return nil
+		}
+	}
+	cluster.CircuitBreakers.Thresholds = append(cluster.CircuitBreakers.Thresholds, &clusterv3.CircuitBreakers_Thresholds{
+		Priority:   corev3.RoutingPriority_DEFAULT,
+		MaxRetries: wrapperspb.UInt32(uint32(limit.Static.MaxParallel)),
+	})
+
+	return nil
+}
+
+// applyRetryLimit dispatches to the circuit breaker mapping for limit's
+// Type, leaving cluster untouched when limit is nil or its Type is empty,
+// and returning an error for an unrecognized Type.
+func applyRetryLimit(cluster *clusterv3.Cluster, limit *egv1a1.RetryLimitPolicy) error {
+	if limit == nil {
+		return nil
+	}
+	switch limit.Type {
+	case egv1a1.RetryLimitTypeRetryBudget:
+		return applyRetryBudget(cluster, limit)
+	case egv1a1.RetryLimitTypeStatic:
+		return applyStaticRetryLimit(cluster, limit)
+	case "":
+		return nil
+	default:
+		return fmt.Errorf("unsupported retry limit type %q", limit.Type)
+	}
+}
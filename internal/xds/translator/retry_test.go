@@ -0,0 +1,243 @@
+// Copyright Envoy Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	"testing"
+
+	clusterv3 "github.com/envoyproxy/go-control-plane/envoy/config/cluster/v3"
+	omitcanaryhostsv3 "github.com/envoyproxy/go-control-plane/envoy/extensions/retry/host/omit_canary_hosts/v3"
+	omithostmetadatav3 "github.com/envoyproxy/go-control-plane/envoy/extensions/retry/host/omit_host_metadata/v3"
+	prevpriov3 "github.com/envoyproxy/go-control-plane/envoy/extensions/retry/priority/previous_priorities/v3"
+	"github.com/golang/protobuf/ptypes/duration"
+
+	egv1a1 "github.com/envoyproxy/gateway/api/v1alpha1"
+)
+
+func TestBuildRetryPolicyHTTP(t *testing.T) {
+	rs := &egv1a1.RetryStrategy{
+		Type: egv1a1.ProtocolTypeHttp,
+		Http: &egv1a1.HttpRetry{
+			RetryOn:              egv1a1.RetryOn5xx,
+			RetriableStatusCodes: egv1a1.RetriableStatusCodes{502, 503},
+			RetryHostPredicate:   &egv1a1.RetryHostPredicate{PreviousHosts: true},
+		},
+		NumRetries: 3,
+		PerRetry: egv1a1.PerRetryPolicy{
+			Timeout: duration.Duration{Seconds: 1},
+		},
+	}
+
+	rp, err := buildRetryPolicy(rs)
+	if err != nil {
+		t.Fatalf("buildRetryPolicy returned error: %v", err)
+	}
+	if rp.GetRetryOn() != "5xx" {
+		t.Fatalf("expected retry_on %q, got %q", "5xx", rp.GetRetryOn())
+	}
+	if rp.GetNumRetries().GetValue() != 3 {
+		t.Fatalf("expected num_retries 3, got %d", rp.GetNumRetries().GetValue())
+	}
+	if rp.GetPerTryTimeout().GetSeconds() != 1 {
+		t.Fatalf("expected per_try_timeout 1s, got %v", rp.GetPerTryTimeout())
+	}
+	if len(rp.GetRetryHostPredicate()) != 1 || rp.GetRetryHostPredicate()[0].GetName() != previousHostsRetryHostPredicateName {
+		t.Fatalf("expected previous_hosts predicate, got %v", rp.GetRetryHostPredicate())
+	}
+	if len(rp.GetRetriableStatusCodes()) != 2 {
+		t.Fatalf("expected 2 retriable status codes, got %v", rp.GetRetriableStatusCodes())
+	}
+}
+
+func TestBuildRetryPolicyRetryPriorityEmitsTypedConfig(t *testing.T) {
+	rs := &egv1a1.RetryStrategy{
+		Type: egv1a1.ProtocolTypeHttp,
+		Http: &egv1a1.HttpRetry{
+			RetryOn:       egv1a1.RetryOn5xx,
+			RetryPriority: &egv1a1.RetryPriority{UpdateFrequency: 4},
+		},
+	}
+
+	rp, err := buildRetryPolicy(rs)
+	if err != nil {
+		t.Fatalf("buildRetryPolicy returned error: %v", err)
+	}
+
+	any := rp.GetRetryPriority().GetTypedConfig()
+	cfg := &prevpriov3.PreviousPrioritiesConfig{}
+	if err := any.UnmarshalTo(cfg); err != nil {
+		t.Fatalf("retry_priority typed_config did not unmarshal as PreviousPrioritiesConfig: %v", err)
+	}
+	if cfg.UpdateFrequency != 4 {
+		t.Fatalf("expected update_frequency 4, got %d", cfg.UpdateFrequency)
+	}
+}
+
+func TestBuildRetryPolicyHostPredicatesEmitTypedConfig(t *testing.T) {
+	rs := &egv1a1.RetryStrategy{
+		Type: egv1a1.ProtocolTypeHttp,
+		Http: &egv1a1.HttpRetry{
+			RetryOn: egv1a1.RetryOn5xx,
+			RetryHostPredicate: &egv1a1.RetryHostPredicate{
+				OmitCanaryHosts:  true,
+				OmitHostMetadata: map[string]string{"canary": "true"},
+			},
+		},
+	}
+
+	rp, err := buildRetryPolicy(rs)
+	if err != nil {
+		t.Fatalf("buildRetryPolicy returned error: %v", err)
+	}
+
+	predicates := rp.GetRetryHostPredicate()
+	if len(predicates) != 2 {
+		t.Fatalf("expected omit_canary_hosts and omit_host_metadata predicates, got %v", predicates)
+	}
+
+	canary := &omitcanaryhostsv3.OmitCanaryHostsPredicate{}
+	if err := predicates[0].GetTypedConfig().UnmarshalTo(canary); err != nil {
+		t.Fatalf("omit_canary_hosts predicate did not carry a typed_config: %v", err)
+	}
+
+	metadata := &omithostmetadatav3.OmitHostMetadataConfig{}
+	if err := predicates[1].GetTypedConfig().UnmarshalTo(metadata); err != nil {
+		t.Fatalf("omit_host_metadata predicate did not unmarshal: %v", err)
+	}
+	value := metadata.GetMetadataMatch().GetFilterMetadata()[lbMetadataNamespace].GetFields()["canary"].GetStringValue()
+	if value != "true" {
+		t.Fatalf("expected metadata_match envoy.lb/canary=true, got %q", value)
+	}
+}
+
+func TestBuildRetryPolicyRateLimitedBackOff(t *testing.T) {
+	rs := &egv1a1.RetryStrategy{
+		Type: egv1a1.ProtocolTypeHttp,
+		Http: &egv1a1.HttpRetry{
+			RetryOn: egv1a1.RetryOn5xx,
+			RateLimitedBackOff: &egv1a1.RateLimitedRetryBackOff{
+				ResetHeaders: []egv1a1.ResetHeader{{Name: "Retry-After", Format: egv1a1.ResetHeaderFormatSeconds}},
+			},
+		},
+	}
+
+	rp, err := buildRetryPolicy(rs)
+	if err != nil {
+		t.Fatalf("buildRetryPolicy returned error: %v", err)
+	}
+	if rp.GetRateLimitedRetryBackOff().GetMaxInterval() != nil {
+		t.Fatalf("expected no max_interval when unset, got %v", rp.GetRateLimitedRetryBackOff().GetMaxInterval())
+	}
+
+	_, err = buildRetryPolicy(&egv1a1.RetryStrategy{
+		Type: egv1a1.ProtocolTypeHttp,
+		Http: &egv1a1.HttpRetry{
+			RetryOn:            egv1a1.RetryOn5xx,
+			RateLimitedBackOff: &egv1a1.RateLimitedRetryBackOff{},
+		},
+	})
+	if err == nil {
+		t.Fatal("expected an error when rateLimitedBackOff has no resetHeaders")
+	}
+}
+
+func TestBuildRetryPolicyRequiresHttpOrGrpc(t *testing.T) {
+	if _, err := buildRetryPolicy(&egv1a1.RetryStrategy{Type: egv1a1.ProtocolTypeHttp}); err == nil {
+		t.Fatal("expected an error when type is Http but Http is unset")
+	}
+	if _, err := buildRetryPolicy(&egv1a1.RetryStrategy{Type: egv1a1.ProtocolTypeGrpc}); err == nil {
+		t.Fatal("expected an error when type is Grpc but Grpc is unset")
+	}
+}
+
+func TestBuildHedgePolicy(t *testing.T) {
+	if got := buildHedgePolicy(nil); got != nil {
+		t.Fatalf("expected nil for a nil HedgedRetry, got %v", got)
+	}
+
+	hp := buildHedgePolicy(&egv1a1.HedgedRetry{HedgeOnPerTryTimeout: true})
+	if !hp.GetHedgeOnPerTryTimeout() {
+		t.Fatal("expected hedge_on_per_try_timeout to be true")
+	}
+}
+
+func TestBuildRetryPolicyAppliesHedgeJitter(t *testing.T) {
+	rs := &egv1a1.RetryStrategy{
+		Type: egv1a1.ProtocolTypeHttp,
+		Http: &egv1a1.HttpRetry{
+			RetryOn: egv1a1.RetryOn5xx,
+			HedgedRetry: &egv1a1.HedgedRetry{
+				HedgeOnPerTryTimeout: true,
+				InitialJitter:        duration.Duration{Seconds: 1},
+				MaxJitter:            duration.Duration{Seconds: 5},
+			},
+		},
+	}
+
+	rp, err := buildRetryPolicy(rs)
+	if err != nil {
+		t.Fatalf("buildRetryPolicy returned error: %v", err)
+	}
+	if rp.GetRetryBackOff().GetBaseInterval().GetSeconds() != 1 {
+		t.Fatalf("expected retry_back_off.base_interval 1s from InitialJitter, got %v", rp.GetRetryBackOff().GetBaseInterval())
+	}
+	if rp.GetRetryBackOff().GetMaxInterval().GetSeconds() != 5 {
+		t.Fatalf("expected retry_back_off.max_interval 5s from MaxJitter, got %v", rp.GetRetryBackOff().GetMaxInterval())
+	}
+}
+
+func TestApplyRetryLimitBudget(t *testing.T) {
+	cluster := &clusterv3.Cluster{}
+	limit := &egv1a1.RetryLimitPolicy{
+		Type: egv1a1.RetryLimitTypeRetryBudget,
+		RetryBudget: egv1a1.RetryBudgetPolicy{
+			ActiveRequestPercent: 25,
+			MinConcurrent:        5,
+		},
+	}
+
+	if err := applyRetryLimit(cluster, limit); err != nil {
+		t.Fatalf("applyRetryLimit returned error: %v", err)
+	}
+
+	thresholds := cluster.GetCircuitBreakers().GetThresholds()
+	if len(thresholds) != 1 {
+		t.Fatalf("expected a single threshold, got %d", len(thresholds))
+	}
+	budget := thresholds[0].GetRetryBudget()
+	if budget.GetBudgetPercent().GetValue() != 25 {
+		t.Fatalf("expected budget_percent 25, got %v", budget.GetBudgetPercent())
+	}
+	if budget.GetMinRetryConcurrency().GetValue() != 5 {
+		t.Fatalf("expected min_retry_concurrency 5, got %v", budget.GetMinRetryConcurrency())
+	}
+}
+
+func TestApplyRetryLimitStatic(t *testing.T) {
+	cluster := &clusterv3.Cluster{}
+	limit := &egv1a1.RetryLimitPolicy{
+		Type:   egv1a1.RetryLimitTypeStatic,
+		Static: egv1a1.StaticPolicy{MaxParallel: 10},
+	}
+
+	if err := applyRetryLimit(cluster, limit); err != nil {
+		t.Fatalf("applyRetryLimit returned error: %v", err)
+	}
+
+	thresholds := cluster.GetCircuitBreakers().GetThresholds()
+	if len(thresholds) != 1 || thresholds[0].GetMaxRetries().GetValue() != 10 {
+		t.Fatalf("expected max_retries 10, got %v", thresholds)
+	}
+}
+
+func TestApplyRetryLimitRejectsUnknownType(t *testing.T) {
+	cluster := &clusterv3.Cluster{}
+	limit := &egv1a1.RetryLimitPolicy{Type: egv1a1.RetryLimitType("bogus")}
+
+	if err := applyRetryLimit(cluster, limit); err == nil {
+		t.Fatal("expected an error for an unrecognized retry limit type")
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright Envoy Gateway Authors
+// SPDX-License-Identifier: Apache-2.0
+// The full text of the Apache license is available in the LICENSE file at
+// the root of the repo.
+
+package translator
+
+import (
+	routev3 "github.com/envoyproxy/go-control-plane/envoy/config/route/v3"
+	matcherv3 "github.com/envoyproxy/go-control-plane/envoy/type/matcher/v3"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// httpRouteMatchToRouteMatch translates a Gateway API HTTPRouteMatch into
+// the Envoy route.RouteMatch used by jwt_authn requirement rules. A nil
+// match applies to every request on the route.
+func httpRouteMatchToRouteMatch(match *gwapiv1.HTTPRouteMatch) *routev3.RouteMatch {
+	if match == nil {
+		return &routev3.RouteMatch{
+			PathSpecifier: &routev3.RouteMatch_Prefix{Prefix: "/"},
+		}
+	}
+
+	out := &routev3.RouteMatch{
+		PathSpecifier: &routev3.RouteMatch_Prefix{Prefix: "/"},
+	}
+
+	if match.Path != nil && match.Path.Value != nil {
+		switch {
+		case match.Path.Type != nil && *match.Path.Type == gwapiv1.PathMatchExact:
+			out.PathSpecifier = &routev3.RouteMatch_Path{Path: *match.Path.Value}
+		default:
+			out.PathSpecifier = &routev3.RouteMatch_Prefix{Prefix: *match.Path.Value}
+		}
+	}
+
+	for _, h := range match.Headers {
+		out.Headers = append(out.Headers, &routev3.HeaderMatcher{
+			Name: string(h.Name),
+			HeaderMatchSpecifier: &routev3.HeaderMatcher_StringMatch{
+				StringMatch: &matcherv3.StringMatcher{
+					MatchPattern: &matcherv3.StringMatcher_Exact{Exact: h.Value},
+				},
+			},
+		})
+	}
+
+	return out
+}